@@ -0,0 +1,111 @@
+package evatr
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests used by the
+// batch functions if no concurrency is given.
+const DefaultBatchConcurrency = 4
+
+// BatchResult is the outcome of a single request of a batch.
+type BatchResult struct {
+	// Position of the request in the input
+	Index int
+
+	// Request as passed in
+	Request ValidationRequest
+
+	// Response if the validation succeeded
+	Response *ValidationResponse
+
+	// Error if the validation failed or was not attempted
+	Err error
+}
+
+// ValidateBatch validates the requests with at most concurrency requests in
+// flight. The results are in input order and there is one result per request.
+// If ctx is done, requests that were not started fail with the context's
+// error, which is also returned if at least one request failed with it.
+func (c *Client) ValidateBatch(ctx context.Context, requests []ValidationRequest, concurrency int) ([]BatchResult, error) {
+	results, err := c.ValidateBatchSeq(ctx, slices.Values(requests), concurrency)
+	for i := len(results); i < len(requests); i++ {
+		results = append(results, BatchResult{Index: i, Request: requests[i], Err: err})
+	}
+	return results, err
+}
+
+// ValidateBatchSeq is like ValidateBatch but reads the requests from an
+// iterator. If ctx is done, the iterator is not consumed any further and the
+// results only cover the requests read so far; the last of them failed with
+// the context's error.
+func (c *Client) ValidateBatchSeq(ctx context.Context, requests iter.Seq[ValidationRequest], concurrency int) ([]BatchResult, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	type job struct {
+		index   int
+		request ValidationRequest
+	}
+
+	var (
+		mu      sync.Mutex
+		results []BatchResult
+		wg      sync.WaitGroup
+	)
+
+	jobs := make(chan job)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				resp, err := c.ValidateVATWithRequest(ctx, &j.request)
+
+				mu.Lock()
+				results[j.index].Response = resp
+				results[j.index].Err = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	index := 0
+	for req := range requests {
+		mu.Lock()
+		results = append(results, BatchResult{Index: index, Request: req})
+		mu.Unlock()
+
+		if ctx.Err() == nil {
+			select {
+			case jobs <- job{index: index, request: req}:
+				index++
+				continue
+			case <-ctx.Done():
+			}
+		}
+
+		mu.Lock()
+		results[index].Err = ctx.Err()
+		mu.Unlock()
+		break
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	// A batch completed before ctx was done is not an error.
+	if err := ctx.Err(); err != nil {
+		for _, result := range results {
+			if errors.Is(result.Err, err) {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,129 @@
+package evatr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateBatch tests concurrent batch validation
+func TestValidateBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		var req evatr.ValidationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// answer out of order
+		time.Sleep(time.Duration(len(req.RequestedVATID)%3) * 5 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.RequestedVATID == "ATU00000000" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusVATIDNotAssigned})
+			return
+		}
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{ID: req.RequestedVATID, Status: evatr.StatusValid})
+	}))
+	defer server.Close()
+
+	client := evatr.NewClient(evatr.WithBaseURL(server.URL))
+
+	t.Run("results in input order", func(t *testing.T) {
+		var requests []evatr.ValidationRequest
+		for i := range 20 {
+			requests = append(requests, evatr.ValidationRequest{
+				RequestingVATID: "DE123456789",
+				RequestedVATID:  fmt.Sprintf("ATU%0*d", 8+i%3, i+1),
+			})
+		}
+		requests[7].RequestedVATID = "ATU00000000"
+
+		results, err := client.ValidateBatch(t.Context(), requests, 3)
+		require.NoError(t, err)
+		require.Len(t, results, len(requests))
+
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+			assert.Equal(t, requests[i], result.Request)
+			if i == 7 {
+				assert.Error(t, result.Err)
+				assert.Nil(t, result.Response)
+				continue
+			}
+			require.NoError(t, result.Err)
+			assert.Equal(t, requests[i].RequestedVATID, result.Response.ID)
+		}
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		requests := make([]evatr.ValidationRequest, 5)
+		for i := range requests {
+			requests[i] = evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"}
+		}
+
+		results, err := client.ValidateBatch(ctx, requests, 2)
+		assert.True(t, errors.Is(err, context.Canceled))
+		require.Len(t, results, 5)
+		for _, result := range results {
+			assert.Error(t, result.Err)
+		}
+	})
+
+	t.Run("completed before cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		requests := []evatr.ValidationRequest{{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"}}
+		seq := func(yield func(evatr.ValidationRequest) bool) {
+			for _, req := range requests {
+				if !yield(req) {
+					return
+				}
+			}
+			// wait for the last request before cancelling
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}
+
+		results, err := client.ValidateBatchSeq(ctx, seq, 1)
+		require.Error(t, ctx.Err())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.NoError(t, results[0].Err)
+	})
+
+	t.Run("iterator", func(t *testing.T) {
+		seq := func(yield func(evatr.ValidationRequest) bool) {
+			for range 3 {
+				if !yield(evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"}) {
+					return
+				}
+			}
+		}
+
+		results, err := client.ValidateBatchSeq(t.Context(), seq, 0)
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+	})
+}
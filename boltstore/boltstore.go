@@ -0,0 +1,242 @@
+// Package boltstore keeps validation results in a bbolt database file.
+//
+//	store, err := boltstore.Open("history.db")
+//	...
+//	defer store.Close()
+//
+//	client := evatr.NewClient(evatr.WithStore(store, evatr.StoreOptions{}))
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the database. The index keys end with the CheckedAt time and
+// the sequence number, so records are ordered by time within an index.
+var (
+	bucketRecords = []byte("records") // seq -> JSON record
+	bucketByTime  = []byte("by_time") // time, seq -> seq
+	bucketByVATID = []byte("by_vat")  // VAT ID, 0, time, seq -> seq
+)
+
+// Store is an evatr.Store in a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ evatr.Store = (*Store)(nil)
+
+// Open opens or creates a database file. Only one process can open it at a
+// time; Open waits up to a second for the file lock.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketRecords, bucketByTime, bucketByVATID} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("boltstore: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Insert implements evatr.Store.
+func (s *Store) Insert(_ context.Context, rec evatr.ValidationRecord) (uint64, error) {
+	rec.Request.RequestedVATID = vatid.Normalize(rec.Request.RequestedVATID)
+	if rec.CheckedAt.IsZero() {
+		rec.CheckedAt = time.Now()
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		records := tx.Bucket(bucketRecords)
+
+		seq, err := records.NextSequence()
+		if err != nil {
+			return err
+		}
+		rec.Seq = seq
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		id := encodeSeq(seq)
+		suffix := indexSuffix(rec.CheckedAt, seq)
+		if err := records.Put(id, data); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketByTime).Put(suffix, id); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketByVATID).Put(append(vatPrefix(rec.Request.RequestedVATID), suffix...), id)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("boltstore: insert: %w", err)
+	}
+	return rec.Seq, nil
+}
+
+// Query implements evatr.Store.
+func (s *Store) Query(_ context.Context, q evatr.RecordQuery) (evatr.RecordPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = evatr.DefaultPageSize
+	}
+
+	bucket := bucketByTime
+	var prefix []byte
+	if vatID := vatid.Normalize(q.VATID); vatID != "" {
+		bucket = bucketByVATID
+		prefix = vatPrefix(vatID)
+	}
+
+	var cursor []byte
+	if q.Cursor != "" {
+		var err error
+		cursor, err = hex.DecodeString(q.Cursor)
+		if err != nil || !bytes.HasPrefix(cursor, prefix) || len(cursor) != len(prefix)+16 {
+			return evatr.RecordPage{}, fmt.Errorf("%w %q", evatr.ErrInvalidCursor, q.Cursor)
+		}
+	}
+
+	var page evatr.RecordPage
+	err := s.db.View(func(tx *bolt.Tx) error {
+		records := tx.Bucket(bucketRecords)
+		c := tx.Bucket(bucket).Cursor()
+
+		var from, until []byte
+		if !q.From.IsZero() {
+			from = append(bytes.Clone(prefix), encodeTime(q.From)...)
+		}
+		if !q.Until.IsZero() {
+			until = append(bytes.Clone(prefix), encodeTime(q.Until)...)
+		}
+
+		var k, v []byte
+		if q.Descending {
+			k, v = seekLast(c, prefix, until, cursor)
+		} else {
+			k, v = seekFirst(c, prefix, from, cursor)
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = step(c, q.Descending) {
+			if !q.Descending && until != nil && bytes.Compare(k, until) >= 0 {
+				break
+			}
+			if q.Descending && from != nil && bytes.Compare(k, from) < 0 {
+				break
+			}
+
+			if len(page.Records) == limit {
+				page.NextCursor = hex.EncodeToString(lastKey(prefix, page.Records[limit-1]))
+				break
+			}
+
+			var rec evatr.ValidationRecord
+			if err := json.Unmarshal(records.Get(v), &rec); err != nil {
+				return fmt.Errorf("record %d: %w", binary.BigEndian.Uint64(v), err)
+			}
+			page.Records = append(page.Records, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return evatr.RecordPage{}, fmt.Errorf("boltstore: query: %w", err)
+	}
+	return page, nil
+}
+
+// seekFirst positions the cursor on the first key after the page cursor or
+// at the start of the range.
+func seekFirst(c *bolt.Cursor, prefix, from, after []byte) ([]byte, []byte) {
+	switch {
+	case after != nil:
+		k, v := c.Seek(after)
+		if bytes.Equal(k, after) {
+			k, v = c.Next()
+		}
+		return k, v
+	case from != nil:
+		return c.Seek(from)
+	default:
+		return c.Seek(prefix)
+	}
+}
+
+// seekLast positions the cursor on the last key before the page cursor or
+// the end of the range.
+func seekLast(c *bolt.Cursor, prefix, until, before []byte) ([]byte, []byte) {
+	end := before
+	switch {
+	case end != nil:
+	case until != nil:
+		end = until
+	case len(prefix) > 0:
+		// The prefix ends with 0, the next VAT ID starts after 1.
+		end = append(bytes.Clone(prefix[:len(prefix)-1]), 1)
+	}
+
+	if end == nil {
+		return c.Last()
+	}
+	if k, _ := c.Seek(end); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+func step(c *bolt.Cursor, descending bool) ([]byte, []byte) {
+	if descending {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+// lastKey returns the index key of a record as page cursor.
+func lastKey(prefix []byte, rec evatr.ValidationRecord) []byte {
+	return append(bytes.Clone(prefix), indexSuffix(rec.CheckedAt, rec.Seq)...)
+}
+
+func vatPrefix(vatID string) []byte {
+	return append([]byte(vatID), 0)
+}
+
+func indexSuffix(t time.Time, seq uint64) []byte {
+	return append(encodeTime(t), encodeSeq(seq)...)
+}
+
+// encodeTime encodes a time so that byte order is time order, including
+// times before 1970.
+func encodeTime(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano())^(1<<63))
+}
+
+func encodeSeq(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
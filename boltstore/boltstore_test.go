@@ -0,0 +1,54 @@
+package boltstore_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/boltstore"
+	"github.com/hostwithquantum/go-evatr/storetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore tests the store against the evatr.Store contract
+func TestStore(t *testing.T) {
+	storetest.TestStore(t, func(t *testing.T) evatr.Store {
+		store, err := boltstore.Open(filepath.Join(t.TempDir(), "history.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+// TestReopen tests records survive closing the database
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	checkedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	store, err := boltstore.Open(path)
+	require.NoError(t, err)
+	seq, err := store.Insert(t.Context(), evatr.ValidationRecord{
+		Request:   evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"},
+		Response:  &evatr.ValidationResponse{ID: "abc", Status: evatr.StatusValid},
+		CheckedAt: checkedAt,
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	store, err = boltstore.Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	page, err := store.Query(t.Context(), evatr.RecordQuery{VATID: "ATU12345678"})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, seq, page.Records[0].Seq)
+	assert.Equal(t, "abc", page.Records[0].Response.ID)
+	assert.True(t, checkedAt.Equal(page.Records[0].CheckedAt))
+
+	next, err := store.Insert(t.Context(), evatr.ValidationRecord{Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}})
+	require.NoError(t, err)
+	assert.Equal(t, seq+1, next)
+}
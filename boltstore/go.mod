@@ -0,0 +1,17 @@
+module github.com/hostwithquantum/go-evatr/boltstore
+
+go 1.24.0
+
+require (
+	github.com/hostwithquantum/go-evatr v0.0.0-20261016190609-6bdc471bcaf7
+	github.com/stretchr/testify v1.11.1
+	go.etcd.io/bbolt v1.4.3
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,195 @@
+package evatr
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached answer of the API, either a response or an error.
+type CacheEntry struct {
+	Response *ValidationResponse `json:"response,omitempty"`
+	Err      *Error              `json:"error,omitempty"`
+}
+
+// Cache stores validation results. Implementations must be safe for
+// concurrent use and are responsible for expiring entries after their TTL.
+type Cache interface {
+	// Get returns the entry for key if present and not expired.
+	Get(ctx context.Context, key string) (CacheEntry, bool)
+
+	// Set stores the entry for key for the given duration.
+	Set(ctx context.Context, key string, entry CacheEntry, ttl time.Duration)
+}
+
+// CachePolicy decides how long answers are cached, based on their eVatR
+// status. Statuses without a TTL are not cached. Transient statuses are never
+// cached, regardless of the policy.
+type CachePolicy struct {
+	TTL map[StatusCode]time.Duration
+}
+
+// DefaultCachePolicy caches definite answers for a day and answers that may
+// change soon for a shorter time.
+var DefaultCachePolicy = CachePolicy{
+	TTL: map[StatusCode]time.Duration{
+		StatusValid:                 24 * time.Hour,
+		StatusValidWithSpecialCase:  24 * time.Hour,
+		StatusNoLongerValid:         24 * time.Hour,
+		StatusNotYetValid:           time.Hour,
+		StatusVATIDNotAssigned:      15 * time.Minute,
+		StatusInvalidRequestedVATID: time.Hour,
+		StatusInvalidVATIDFormat:    time.Hour,
+	},
+}
+
+// ttl returns how long an entry with the given status is cached.
+func (p CachePolicy) ttl(status StatusCode) time.Duration {
+	if status == "" || status.IsTransient() {
+		return 0
+	}
+	return p.TTL[status]
+}
+
+// WithCache caches the results of the ValidateVAT* calls.
+func WithCache(cache Cache, policy CachePolicy) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cachePolicy = policy
+	}
+}
+
+// cacheKey returns the cache key of a request with normalized VAT IDs.
+func cacheKey(req *ValidationRequest) string {
+	normalized := ValidationRequest{
+		RequestingVATID: req.RequestingVATID,
+		RequestedVATID:  req.RequestedVATID,
+		CompanyName:     strings.TrimSpace(req.CompanyName),
+		Street:          strings.TrimSpace(req.Street),
+		PostalCode:      strings.TrimSpace(req.PostalCode),
+		City:            strings.TrimSpace(req.City),
+	}
+
+	data, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedValidate answers from the cache if possible and stores new answers
+// according to the cache policy.
+// The returned bool reports a cache hit.
+func (c *Client) cachedValidate(ctx context.Context, req *ValidationRequest, validate func() (*ValidationResponse, error)) (*ValidationResponse, bool, error) {
+	key := cacheKey(req)
+	if entry, ok := c.cache.Get(ctx, key); ok {
+		if entry.Err != nil {
+			errCopy := *entry.Err
+			return nil, true, &errCopy
+		}
+		if entry.Response != nil {
+			respCopy := *entry.Response
+			return &respCopy, true, nil
+		}
+	}
+
+	resp, err := validate()
+	if err != nil {
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			if ttl := c.cachePolicy.ttl(apiErr.Status); ttl > 0 {
+				errCopy := *apiErr
+				c.cache.Set(ctx, key, CacheEntry{Err: &errCopy}, ttl)
+			}
+		}
+		return nil, false, err
+	}
+
+	if ttl := c.cachePolicy.ttl(resp.Status); ttl > 0 {
+		respCopy := *resp
+		c.cache.Set(ctx, key, CacheEntry{Response: &respCopy}, ttl)
+	}
+	return resp, false, nil
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entries.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruItem struct {
+	key       string
+	entry     CacheEntry
+	expiresAt time.Time
+}
+
+// NewLRUCache returns an in-memory cache holding up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache.
+func (l *LRUCache) Get(_ context.Context, key string) (CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	item := el.Value.(*lruItem)
+	if !l.now().Before(item.expiresAt) {
+		l.order.Remove(el)
+		delete(l.items, key)
+		return CacheEntry{}, false
+	}
+
+	l.order.MoveToFront(el)
+	return item.entry, true
+}
+
+// Set implements Cache.
+func (l *LRUCache) Set(_ context.Context, key string, entry CacheEntry, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := l.now().Add(ttl)
+	if el, ok := l.items[key]; ok {
+		item := el.Value.(*lruItem)
+		item.entry = entry
+		item.expiresAt = expiresAt
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruItem{key: key, entry: entry, expiresAt: expiresAt})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,113 @@
+package evatr_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLRUCache tests expiry and eviction of the in-memory cache
+func TestLRUCache(t *testing.T) {
+	cache := evatr.NewLRUCache(2)
+	entry := evatr.CacheEntry{Response: &evatr.ValidationResponse{Status: evatr.StatusValid}}
+
+	cache.Set(t.Context(), "a", entry, time.Hour)
+	cache.Set(t.Context(), "b", entry, time.Hour)
+
+	_, ok := cache.Get(t.Context(), "a")
+	assert.True(t, ok)
+
+	// "b" is the least recently used entry now
+	cache.Set(t.Context(), "c", entry, time.Hour)
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get(t.Context(), "b")
+	assert.False(t, ok)
+
+	cache.Set(t.Context(), "d", entry, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get(t.Context(), "d")
+	assert.False(t, ok)
+}
+
+// TestClientCache tests caching of validation results per status
+func TestClientCache(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		var req evatr.ValidationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.RequestedVATID {
+		case "ATU99999999":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusVATIDNotAssigned})
+		case "ATU55555555":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable1})
+		default:
+			json.NewEncoder(w).Encode(evatr.ValidationResponse{
+				RequestTimestamp: evatr.Time{Time: time.Now()},
+				Status:           evatr.StatusValid,
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := evatr.NewClient(
+		evatr.WithBaseURL(server.URL),
+		evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy),
+	)
+
+	t.Run("valid result is cached for normalized requests", func(t *testing.T) {
+		calls = 0
+		first, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+
+		second, err := client.ValidateVAT(t.Context(), "de 123 456 789", "atu-12345678")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+		assert.NotSame(t, first, second)
+	})
+
+	t.Run("qualified requests are cached separately", func(t *testing.T) {
+		calls = 0
+		_, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Test GmbH", "Wien", "", "")
+		require.NoError(t, err)
+		_, err = client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Test GmbH ", "Wien", "", "")
+		require.NoError(t, err)
+		_, err = client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Other GmbH", "Wien", "", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("not assigned error is cached", func(t *testing.T) {
+		calls = 0
+		for range 2 {
+			_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+			require.Error(t, err)
+			assert.Equal(t, evatr.StatusVATIDNotAssigned, err.(*evatr.Error).Status)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("transient errors are never cached", func(t *testing.T) {
+		calls = 0
+		for range 2 {
+			_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU55555555")
+			require.Error(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,180 @@
+package evatr
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCatalogRefreshInterval is how often StatusCatalog.Run refreshes
+// the live status messages by default.
+const DefaultCatalogRefreshInterval = 24 * time.Hour
+
+// embeddedStatusMessages is a snapshot of /v1/info/statusmeldungen.
+//
+//go:embed statusmeldungen.json
+var embeddedStatusMessages []byte
+
+// EmbeddedStatusMessages returns the snapshot of the status messages shipped
+// with the module, sorted by status code.
+func EmbeddedStatusMessages() []StatusMessage {
+	var messages []StatusMessage
+	if err := json.Unmarshal(embeddedStatusMessages, &messages); err != nil {
+		panic("evatr: invalid embedded status messages: " + err.Error())
+	}
+	return messages
+}
+
+// CatalogOptions configures a StatusCatalog.
+type CatalogOptions struct {
+	// Interval between refreshes in Run (defaults to DefaultCatalogRefreshInterval)
+	RefreshInterval time.Duration
+
+	// Called after a refresh with the live messages the snapshot does not know
+	OnUnknownCodes func([]StatusMessage)
+
+	// Called when a refresh in Run fails; the catalog keeps its previous data
+	OnRefreshError func(error)
+}
+
+// StatusCatalog looks up status messages. It prefers the live list fetched
+// with GetStatusMessages and falls back to the embedded snapshot, e.g.
+// before the first refresh or during the maintenance window.
+type StatusCatalog struct {
+	client   *Client
+	opts     CatalogOptions
+	embedded map[StatusCode]StatusMessage
+
+	mu          sync.RWMutex
+	live        map[StatusCode]StatusMessage
+	lastRefresh time.Time
+	unknown     []StatusMessage
+}
+
+// NewStatusCatalog returns a catalog refreshed with the given client.
+func NewStatusCatalog(client *Client, opts CatalogOptions) *StatusCatalog {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultCatalogRefreshInterval
+	}
+	return &StatusCatalog{
+		client:   client,
+		opts:     opts,
+		embedded: indexMessages(EmbeddedStatusMessages()),
+	}
+}
+
+// Lookup returns the message of a status code, from the live list if it
+// knows the code and from the snapshot otherwise.
+func (c *StatusCatalog) Lookup(code StatusCode) (StatusMessage, bool) {
+	c.mu.RLock()
+	msg, ok := c.live[code]
+	c.mu.RUnlock()
+	if ok {
+		return msg, true
+	}
+
+	msg, ok = c.embedded[code]
+	return msg, ok
+}
+
+// Messages returns the live messages merged with the snapshot, sorted by
+// status code.
+func (c *StatusCatalog) Messages() []StatusMessage {
+	c.mu.RLock()
+	merged := make(map[StatusCode]StatusMessage, len(c.embedded)+len(c.live))
+	for code, msg := range c.embedded {
+		merged[code] = msg
+	}
+	for code, msg := range c.live {
+		merged[code] = msg
+	}
+	c.mu.RUnlock()
+
+	messages := make([]StatusMessage, 0, len(merged))
+	for _, msg := range merged {
+		messages = append(messages, msg)
+	}
+	sortMessages(messages)
+	return messages
+}
+
+// LastRefresh returns the time of the last successful refresh, the zero
+// time if the catalog only knows the snapshot.
+func (c *StatusCatalog) LastRefresh() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastRefresh
+}
+
+// UnknownCodes returns the messages of the last refresh whose codes the
+// snapshot does not know.
+func (c *StatusCatalog) UnknownCodes() []StatusMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.unknown)
+}
+
+// Refresh fetches the live status messages. On error the catalog keeps its
+// previous data.
+func (c *StatusCatalog) Refresh(ctx context.Context) error {
+	messages, err := c.client.GetStatusMessages(ctx)
+	if err != nil {
+		return err
+	}
+
+	var unknown []StatusMessage
+	for _, msg := range messages {
+		if _, ok := c.embedded[msg.Status]; !ok {
+			unknown = append(unknown, msg)
+		}
+	}
+	sortMessages(unknown)
+
+	c.mu.Lock()
+	c.live = indexMessages(messages)
+	c.lastRefresh = time.Now()
+	c.unknown = unknown
+	c.mu.Unlock()
+
+	if len(unknown) > 0 && c.opts.OnUnknownCodes != nil {
+		c.opts.OnUnknownCodes(slices.Clone(unknown))
+	}
+	return nil
+}
+
+// Run refreshes the catalog right away and then periodically until ctx is
+// done.
+func (c *StatusCatalog) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil && c.opts.OnRefreshError != nil {
+			c.opts.OnRefreshError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func indexMessages(messages []StatusMessage) map[StatusCode]StatusMessage {
+	index := make(map[StatusCode]StatusMessage, len(messages))
+	for _, msg := range messages {
+		index[msg.Status] = msg
+	}
+	return index
+}
+
+func sortMessages(messages []StatusMessage) {
+	slices.SortFunc(messages, func(a, b StatusMessage) int {
+		return strings.Compare(string(a.Status), string(b.Status))
+	})
+}
@@ -0,0 +1,114 @@
+package evatr_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmbeddedStatusMessages tests that the snapshot matches the status table
+func TestEmbeddedStatusMessages(t *testing.T) {
+	messages := evatr.EmbeddedStatusMessages()
+	require.Len(t, messages, len(evatr.StatusCodes()))
+
+	for i, msg := range messages {
+		assert.Equal(t, evatr.StatusCodes()[i], msg.Status)
+		assert.Equal(t, msg.Status.HTTPCode(), msg.HTTPCode, msg.Status)
+		assert.Equal(t, msg.Status.Field(), msg.Field, msg.Status)
+		assert.Equal(t, msg.Status.GermanDescription(), msg.Message, msg.Status)
+		assert.Equal(t, msg.Status.IsResult(), msg.Category == "Ergebnis", msg.Status)
+	}
+}
+
+// TestStatusCatalog tests live lookups with fallback to the snapshot
+func TestStatusCatalog(t *testing.T) {
+	var available atomic.Bool
+	available.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]evatr.StatusMessage{
+			{Status: evatr.StatusValid, Category: "Ergebnis", HTTPCode: 200, Message: "Gültig (live)."},
+			{Status: "evatr-9001", Category: "Fehler", HTTPCode: 400, Message: "Neuer Fehler."},
+		})
+	}))
+	defer server.Close()
+
+	var reported []evatr.StatusMessage
+	catalog := evatr.NewStatusCatalog(evatr.NewClient(evatr.WithBaseURL(server.URL)), evatr.CatalogOptions{
+		OnUnknownCodes: func(messages []evatr.StatusMessage) { reported = messages },
+	})
+
+	msg, ok := catalog.Lookup(evatr.StatusValid)
+	require.True(t, ok)
+	assert.Equal(t, evatr.StatusValid.GermanDescription(), msg.Message)
+	assert.True(t, catalog.LastRefresh().IsZero())
+
+	require.NoError(t, catalog.Refresh(t.Context()))
+	assert.False(t, catalog.LastRefresh().IsZero())
+
+	msg, _ = catalog.Lookup(evatr.StatusValid)
+	assert.Equal(t, "Gültig (live).", msg.Message)
+
+	// Codes missing from the live list come from the snapshot.
+	msg, ok = catalog.Lookup(evatr.StatusVATIDNotAssigned)
+	require.True(t, ok)
+	assert.Equal(t, 404, msg.HTTPCode)
+
+	_, ok = catalog.Lookup("evatr-9001")
+	assert.True(t, ok)
+	require.Len(t, reported, 1)
+	assert.Equal(t, evatr.StatusCode("evatr-9001"), reported[0].Status)
+	assert.Equal(t, reported, catalog.UnknownCodes())
+	assert.Len(t, catalog.Messages(), len(evatr.StatusCodes())+1)
+
+	// A failed refresh keeps the live data.
+	available.Store(false)
+	require.Error(t, catalog.Refresh(t.Context()))
+	msg, _ = catalog.Lookup(evatr.StatusValid)
+	assert.Equal(t, "Gültig (live).", msg.Message)
+}
+
+// TestStatusCatalogRun tests periodic refreshes
+func TestStatusCatalogRun(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	var failures atomic.Int32
+	catalog := evatr.NewStatusCatalog(evatr.NewClient(evatr.WithBaseURL(server.URL)), evatr.CatalogOptions{
+		RefreshInterval: 10 * time.Millisecond,
+		OnRefreshError:  func(error) { failures.Add(1) },
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		catalog.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return !catalog.LastRefresh().IsZero() }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, int32(1), failures.Load())
+	assert.GreaterOrEqual(t, requests.Load(), int32(2))
+}
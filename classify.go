@@ -0,0 +1,186 @@
+package evatr
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// Category classifies errors by how callers should react to them.
+type Category int
+
+const (
+	// Unknown or unexpected errors, including canceled contexts
+	CategoryUnknown Category = iota
+
+	// Temporary failures of the API or the network, worth a retry later
+	CategoryTransient
+
+	// Malformed or incomplete input, e.g. a syntactically wrong VAT ID
+	CategoryInvalidInput
+
+	// The requested VAT ID is not assigned
+	CategoryNotAssigned
+
+	// Our own requesting VAT ID is missing, wrong or not authorized, or the
+	// base URL or TLS setup of the client is broken
+	CategoryConfiguration
+
+	// Maximum number of qualified requests of the session reached
+	CategorySessionLimit
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryTransient:
+		return "transient"
+	case CategoryInvalidInput:
+		return "invalid_input"
+	case CategoryNotAssigned:
+		return "not_assigned"
+	case CategoryConfiguration:
+		return "configuration"
+	case CategorySessionLimit:
+		return "session_limit"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Category) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the names returned
+// by String.
+func (c *Category) UnmarshalText(text []byte) error {
+	for category := CategoryUnknown; category <= CategorySessionLimit; category++ {
+		if category.String() == string(text) {
+			*c = category
+			return nil
+		}
+	}
+	return fmt.Errorf("evatr: unknown category %q", text)
+}
+
+// Retryable returns whether the same request may succeed later.
+func (c Category) Retryable() bool {
+	return c == CategoryTransient
+}
+
+// Permanent returns whether the same request will fail again.
+func (c Category) Permanent() bool {
+	return c == CategoryInvalidInput || c == CategoryNotAssigned || c == CategoryConfiguration
+}
+
+// CallerFault returns whether the input of the caller, e.g. a customer,
+// caused the error.
+func (c Category) CallerFault() bool {
+	return c == CategoryInvalidInput || c == CategoryNotAssigned
+}
+
+// ConfigurationFault returns whether our own setup caused the error.
+func (c Category) ConfigurationFault() bool {
+	return c == CategoryConfiguration
+}
+
+// Category returns the category of the API error. Errors without a known
+// eVatR status are classified by their HTTP status code.
+func (e *Error) Category() Category {
+	if e.Status.IsKnown() {
+		return e.Status.Category()
+	}
+
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CategoryTransient
+	}
+	return CategoryUnknown
+}
+
+// Classify returns the category of any error returned by the client.
+func Classify(err error) Category {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Category()
+	}
+
+	switch {
+	case errors.Is(err, ErrMissingRequestingVATID), errors.Is(err, ErrRequestingVATIDNotGerman):
+		return CategoryConfiguration
+	case errors.Is(err, ErrMissingRequest), errors.Is(err, ErrMissingRequestedVATID),
+		errors.Is(err, ErrMissingCompanyName), errors.Is(err, ErrMissingCity):
+		return CategoryInvalidInput
+	case errors.Is(err, ErrMaintenanceWindow), errors.Is(err, context.DeadlineExceeded):
+		return CategoryTransient
+	case errors.Is(err, context.Canceled):
+		return CategoryUnknown
+	}
+
+	var vatErr *vatid.Error
+	if errors.As(err, &vatErr) {
+		return CategoryInvalidInput
+	}
+
+	return classifyNetwork(err)
+}
+
+// classifyNetwork classifies errors of the HTTP client. Timeouts, failed
+// connections and connections closed by the server are transient. A broken
+// base URL or TLS setup fails the same way on every attempt.
+func classifyNetwork(err error) Category {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return CategoryTransient
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &certErr):
+		return CategoryConfiguration
+	case errors.As(err, &urlErr) && urlErr.Op == "parse":
+		return CategoryConfiguration
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return CategoryTransient
+	}
+	if urlErr != nil && (errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF)) {
+		return CategoryTransient
+	}
+	return CategoryUnknown
+}
+
+// IsRetryable returns whether err is temporary and the request may succeed later.
+func IsRetryable(err error) bool {
+	return Classify(err).Retryable()
+}
+
+// IsPermanent returns whether the request will fail again with the same input.
+func IsPermanent(err error) bool {
+	return Classify(err).Permanent()
+}
+
+// IsCallerFault returns whether the VAT ID or company data passed in caused err.
+func IsCallerFault(err error) bool {
+	return Classify(err).CallerFault()
+}
+
+// IsConfigurationFault returns whether our own requesting VAT ID caused err.
+func IsConfigurationFault(err error) bool {
+	return Classify(err).ConfigurationFault()
+}
@@ -0,0 +1,97 @@
+package evatr_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClassify tests the classification of errors
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected evatr.Category
+	}{
+		{"nil", nil, evatr.CategoryUnknown},
+		{"processing error", evatr.NewInternalServerError(evatr.StatusProcessingError2, ""), evatr.CategoryTransient},
+		{"service unavailable", evatr.NewServiceUnavailableError(evatr.StatusServiceUnavailable3, ""), evatr.CategoryTransient},
+		{"gateway timeout", &evatr.Error{StatusCode: 504}, evatr.CategoryTransient},
+		{"plain 500", &evatr.Error{StatusCode: 500}, evatr.CategoryUnknown},
+		{"invalid format", evatr.NewBadRequestError(evatr.StatusInvalidVATIDFormat, ""), evatr.CategoryInvalidInput},
+		{"invalid call", evatr.NewForbiddenError(evatr.StatusInvalidCall, ""), evatr.CategoryInvalidInput},
+		{"not assigned", evatr.NewNotFoundError(evatr.StatusVATIDNotAssigned, ""), evatr.CategoryNotAssigned},
+		{"not authorized", evatr.NewForbiddenError(evatr.StatusNotAuthorizedDE, ""), evatr.CategoryConfiguration},
+		{"requesting not valid", evatr.NewNotFoundError(evatr.StatusRequestingVATIDNotValid, ""), evatr.CategoryConfiguration},
+		{"session limit", evatr.NewBadRequestError(evatr.StatusMaxQualifiedRequestsReached, ""), evatr.CategorySessionLimit},
+		{"wrapped", fmt.Errorf("customer 42: %w", evatr.NewNotFoundError(evatr.StatusVATIDNotAssigned, "")), evatr.CategoryNotAssigned},
+		{"missing requesting", evatr.ErrMissingRequestingVATID, evatr.CategoryConfiguration},
+		{"missing city", evatr.ErrMissingCity, evatr.CategoryInvalidInput},
+		{"preflight", vatid.Validate("ATU1234"), evatr.CategoryInvalidInput},
+		{"maintenance", fmt.Errorf("%w until tomorrow", evatr.ErrMaintenanceWindow), evatr.CategoryTransient},
+		{"connection refused", &url.Error{Op: "Post", URL: "https://example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, evatr.CategoryTransient},
+		{"connection closed", &url.Error{Op: "Post", URL: "https://example.com", Err: io.EOF}, evatr.CategoryTransient},
+		{"client timeout", &url.Error{Op: "Post", URL: "https://example.com", Err: os.ErrDeadlineExceeded}, evatr.CategoryTransient},
+		{"unsupported scheme", &url.Error{Op: "Post", URL: "ftp://example.com", Err: errors.New(`unsupported protocol scheme "ftp"`)}, evatr.CategoryUnknown},
+		{"bad url", &url.Error{Op: "parse", URL: "http://exa mple.com", Err: errors.New("invalid character")}, evatr.CategoryConfiguration},
+		{"certificate", &url.Error{Op: "Post", URL: "https://example.com", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}, evatr.CategoryConfiguration},
+		{"deadline", context.DeadlineExceeded, evatr.CategoryTransient},
+		{"canceled", context.Canceled, evatr.CategoryUnknown},
+		{"other", errors.New("boom"), evatr.CategoryUnknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, evatr.Classify(tc.err))
+		})
+	}
+}
+
+// TestCategory tests the predicates of categories
+func TestCategory(t *testing.T) {
+	assert.True(t, evatr.IsRetryable(evatr.NewServiceUnavailableError(evatr.StatusServiceUnavailable1, "")))
+	assert.False(t, evatr.IsPermanent(evatr.NewServiceUnavailableError(evatr.StatusServiceUnavailable1, "")))
+
+	notAssigned := evatr.NewNotFoundError(evatr.StatusVATIDNotAssigned, "")
+	assert.True(t, evatr.IsPermanent(notAssigned))
+	assert.True(t, evatr.IsCallerFault(notAssigned))
+	assert.False(t, evatr.IsConfigurationFault(notAssigned))
+
+	notAuthorized := evatr.NewForbiddenError(evatr.StatusNotAuthorizedDE, "")
+	assert.True(t, evatr.IsConfigurationFault(notAuthorized))
+	assert.False(t, evatr.IsCallerFault(notAuthorized))
+
+	sessionLimit := evatr.NewBadRequestError(evatr.StatusMaxQualifiedRequestsReached, "")
+	assert.False(t, evatr.IsRetryable(sessionLimit))
+	assert.False(t, evatr.IsPermanent(sessionLimit))
+
+	assert.Equal(t, "session_limit", evatr.CategorySessionLimit.String())
+	assert.Equal(t, evatr.CategoryUnknown, evatr.StatusValid.Category())
+}
+
+// TestCategoryText tests the text encoding of categories
+func TestCategoryText(t *testing.T) {
+	for category := evatr.CategoryUnknown; category <= evatr.CategorySessionLimit; category++ {
+		text, err := category.MarshalText()
+		assert.NoError(t, err)
+
+		var decoded evatr.Category
+		assert.NoError(t, decoded.UnmarshalText(text))
+		assert.Equal(t, category, decoded)
+	}
+
+	var decoded evatr.Category
+	assert.Error(t, decoded.UnmarshalText([]byte("flaky")))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 	"time"
@@ -29,8 +30,28 @@ var version = func() string {
 
 // Client is the eVATR API client.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	preflight   bool
+	retryPolicy RetryPolicy
+
+	maintenance     *MaintenanceSchedule
+	maintenanceMode MaintenanceMode
+
+	limiter Limiter
+
+	cache       Cache
+	cachePolicy CachePolicy
+
+	observers []Observer
+
+	evidence EvidenceRecorder
+
+	store        Store
+	storeOptions StoreOptions
+
+	logger         *slog.Logger
+	loggingOptions LoggingOptions
 }
 
 // Option is a functional option for configuring the Client.
@@ -57,6 +78,14 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithPreflight enables offline syntax and checksum validation of VAT IDs
+// (see package vatid) before a validation request is sent to the API.
+func WithPreflight() Option {
+	return func(c *Client) {
+		c.preflight = true
+	}
+}
+
 // NewClient returns a new eVATR API client.
 func NewClient(opts ...Option) *Client {
 	c := &Client{
@@ -70,26 +99,101 @@ func NewClient(opts ...Option) *Client {
 		opt(c)
 	}
 
+	if c.logger != nil {
+		// Copy the HTTP client, it may be shared with other code.
+		httpClient := *c.httpClient
+		httpClient.Transport = NewLoggingTransport(httpClient.Transport, c.logger, c.loggingOptions)
+		c.httpClient = &httpClient
+	}
+
 	return c
 }
 
+type attemptKey struct{}
+
+// Attempt returns the attempt number (starting at 1) of the request the
+// context belongs to, or 0 outside of the client. Custom transports can use
+// it to tell retries apart.
+func Attempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
 // doRequest performs an HTTP request and handles common error responses.
+// Transient failures are retried according to the client's retry policy.
 func (c *Client) doRequest(ctx context.Context, method, path string, body any, result any) error {
-	var reqBody io.Reader
+	var payload []byte
 	if body != nil {
 		var buf bytes.Buffer
 		if err := json.NewEncoder(&buf).Encode(body); err != nil {
 			return fmt.Errorf("failed to encode request body: %w", err)
 		}
-		reqBody = &buf
+		payload = buf.Bytes()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if path == validationPath {
+			if err := c.awaitMaintenance(ctx); err != nil {
+				return err
+			}
+		}
+
+		err := c.doAttempt(context.WithValue(ctx, attemptKey{}, attempt), method, path, payload, result)
+		if err == nil {
+			return nil
+		}
+
+		wait, retry := c.retryPolicy.next(ctx, attempt, err)
+		if !retry {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// doAttempt performs a single HTTP request.
+func (c *Client) doAttempt(ctx context.Context, method, path string, payload []byte, result any) error {
+	if c.limiter != nil {
+		release, err := c.limiter.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("rate limiter: %w", err)
+		}
+		defer release()
+	}
+
+	ctx, finish := c.startRequest(ctx, RequestInfo{Method: method, Path: path, Attempt: Attempt(ctx)})
+	statusCode, err := c.roundTrip(ctx, method, path, payload, result)
+
+	observed := RequestResult{StatusCode: statusCode, Err: err}
+	if resp, ok := result.(*ValidationResponse); ok && err == nil {
+		observed.Status = resp.Status
+	}
+	finish(observed)
+
+	return err
+}
+
+// roundTrip sends the HTTP request and decodes the response. It returns the
+// HTTP status code if a response was received.
+func (c *Client) roundTrip(ctx context.Context, method, path string, payload []byte, result any) (int, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if body != nil {
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("User-Agent", fmt.Sprintf("go-evatr/%s (+https://github.com/hostwithquantum/go-evatr)", version))
@@ -97,26 +201,51 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body any, r
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	validation, isValidation := result.(*ValidationResponse)
+	if c.evidence == nil || !isValidation {
+		return resp.StatusCode, c.readResponse(resp, resp.Body, result)
+	}
+
+	// Keep the raw answer as evidence, including bytes the decoder skipped.
+	var raw bytes.Buffer
+	body := io.TeeReader(resp.Body, &raw)
+	err = c.readResponse(resp, body, result)
+	if _, copyErr := io.Copy(io.Discard, body); copyErr != nil && err == nil {
+		err = fmt.Errorf("failed to read response: %w", copyErr)
+	}
+
+	if recErr := c.recordExchange(ctx, payload, resp.StatusCode, raw.Bytes(), validation, err); recErr != nil {
+		return resp.StatusCode, recErr
+	}
+	return resp.StatusCode, err
+}
+
+// readResponse decodes a successful response into result or converts an
+// error answer into an *Error.
+func (c *Client) readResponse(resp *http.Response, body io.Reader, result any) error {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		if result != nil {
-			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			if err := json.NewDecoder(body).Decode(result); err != nil {
 				return fmt.Errorf("failed to decode response: %w", err)
 			}
 		}
 		return nil
 	}
 
-	return c.handleErrorResponse(resp.StatusCode, resp.Body)
+	apiErr := c.handleErrorResponse(resp.StatusCode, body)
+	apiErr.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return apiErr
 }
 
 // handleErrorResponse converts HTTP error responses into typed errors.
-func (c *Client) handleErrorResponse(statusCode int, body io.Reader) error {
+func (c *Client) handleErrorResponse(statusCode int, body io.Reader) *Error {
 	var errResp ErrorResponse
-	var status, message string
+	var status StatusCode
+	var message string
 
 	if err := json.NewDecoder(body).Decode(&errResp); err == nil {
 		status = errResp.Status
@@ -135,7 +264,7 @@ func (c *Client) handleErrorResponse(statusCode int, body io.Reader) error {
 }
 
 // getDefaultErrorMessage returns a default error message based on status code and evatr status.
-func getDefaultErrorMessage(statusCode int, status string) string {
+func getDefaultErrorMessage(statusCode int, status StatusCode) string {
 	switch statusCode {
 	case 400:
 		return "Bad request: Invalid input parameters"
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hostwithquantum/go-evatr"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,7 @@ func TestDebugTransport(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		require.NoError(t, json.NewEncoder(w).Encode(evatr.ValidationResponse{
-			RequestTimestamp: "2024-01-01T12:00:00Z",
+			RequestTimestamp: evatr.Time{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
 			Status:           evatr.StatusValid,
 		}))
 	}))
@@ -0,0 +1,194 @@
+package evatr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LoggingOptions configures the logging transport.
+type LoggingOptions struct {
+	// Level of records for successful requests (defaults to slog.LevelDebug)
+	Level slog.Leveler
+
+	// Level of records for error answers and failed requests (defaults to slog.LevelWarn)
+	ErrorLevel slog.Leveler
+
+	// Log request and response bodies
+	LogBodies bool
+
+	// Log bodies of validation requests without redacting VAT IDs and
+	// company data, and bodies of unknown endpoints instead of hiding them
+	Unredacted bool
+}
+
+// infoPath is the path prefix of the info endpoints, whose answers hold no
+// personal data.
+const infoPath = "/v1/info/"
+
+// maxStatusBody limits how much of a response is read for its eVatR status
+// when bodies are not logged. Status answers are far smaller.
+const maxStatusBody = 4 << 10
+
+// WithLogger logs every HTTP request of the client as a slog record.
+func WithLogger(logger *slog.Logger, opts LoggingOptions) Option {
+	return func(c *Client) {
+		c.logger = logger
+		c.loggingOptions = opts
+	}
+}
+
+type loggingTransport struct {
+	transport http.RoundTripper
+	logger    *slog.Logger
+	opts      LoggingOptions
+}
+
+// NewLoggingTransport returns a transport that logs requests and responses
+// with method, path, HTTP status, eVatR status, duration and attempt number.
+func NewLoggingTransport(transport http.RoundTripper, logger *slog.Logger, opts LoggingOptions) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelDebug
+	}
+	if opts.ErrorLevel == nil {
+		opts.ErrorLevel = slog.LevelWarn
+	}
+	return &loggingTransport{transport: transport, logger: logger, opts: opts}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", max(Attempt(ctx), 1)),
+	}
+
+	if t.opts.LogBodies && req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			attrs = append(attrs, slog.String("request_body", t.body(req.URL.Path, data)))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.logger.LogAttrs(ctx, t.opts.ErrorLevel.Level(), "evatr request failed", attrs...)
+		return nil, err
+	}
+
+	data, complete, err := t.peek(resp)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.logger.LogAttrs(ctx, t.opts.ErrorLevel.Level(), "evatr request failed", attrs...)
+		return resp, nil
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	var status struct {
+		Status string `json:"status"`
+	}
+	if complete && json.Unmarshal(data, &status) == nil && status.Status != "" {
+		attrs = append(attrs, slog.String("evatr_status", status.Status))
+	}
+	if t.opts.LogBodies {
+		attrs = append(attrs, slog.String("response_body", t.body(req.URL.Path, data)))
+	}
+
+	level := t.opts.Level.Level()
+	if resp.StatusCode >= 400 {
+		level = t.opts.ErrorLevel.Level()
+	}
+	t.logger.LogAttrs(ctx, level, "evatr request", attrs...)
+
+	return resp, nil
+}
+
+// peek reads the response body for logging and puts it back for the caller.
+// Unless bodies are logged, it reads at most maxStatusBody bytes; complete
+// reports whether data is the whole body.
+func (t *loggingTransport) peek(resp *http.Response) (data []byte, complete bool, err error) {
+	if t.opts.LogBodies {
+		data, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		return data, err == nil, err
+	}
+
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxStatusBody+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	return data, err == nil && len(data) <= maxStatusBody, err
+}
+
+// redactedFields are JSON fields holding VAT IDs or company data.
+var redactedFields = map[string]bool{
+	"anfragendeUstid": true,
+	"angefragteUstid": true,
+	"firmenname":      true,
+	"strasse":         true,
+	"plz":             true,
+	"ort":             true,
+}
+
+// body returns the body for logging, redacted unless configured otherwise.
+// Bodies of the info endpoints are public and logged as they are, those of
+// validations without VAT IDs and company data. Bodies of other endpoints
+// are hidden, as nothing is known about their content.
+func (t *loggingTransport) body(path string, data []byte) string {
+	switch {
+	case t.opts.Unredacted, strings.Contains(path, infoPath):
+		return string(bytes.TrimSpace(data))
+	case strings.HasSuffix(path, validationPath):
+		return redactFields(data)
+	default:
+		return "[redacted]"
+	}
+}
+
+// redactFields redacts the VAT IDs and company data of a validation body.
+func redactFields(data []byte) string {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "[redacted]"
+	}
+
+	for key, value := range fields {
+		if !redactedFields[key] {
+			continue
+		}
+		s, _ := value.(string)
+		fields[key] = redactValue(key, s)
+	}
+
+	redacted, _ := json.Marshal(fields)
+	return string(redacted)
+}
+
+// redactValue keeps the country code of VAT IDs and hides everything else.
+func redactValue(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if (key == "anfragendeUstid" || key == "angefragteUstid") && len(value) > 2 {
+		return value[:2] + "***"
+	}
+	return "***"
+}
@@ -0,0 +1,168 @@
+package evatr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
+
+func TestLoggingTransport(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+	srv.SetResponse("ATU55555555", evatrtest.Response{Status: evatr.StatusServiceUnavailable1})
+
+	t.Run("records without bodies", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		client := srv.Client(evatr.WithLogger(logger, evatr.LoggingOptions{}))
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		_, err = client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+		require.Error(t, err)
+
+		records := logRecords(t, &buf)
+		require.Len(t, records, 2)
+
+		assert.Equal(t, "DEBUG", records[0]["level"])
+		assert.Equal(t, "POST", records[0]["method"])
+		assert.Equal(t, "/v1/abfrage", records[0]["path"])
+		assert.Equal(t, float64(200), records[0]["status"])
+		assert.EqualValues(t, evatr.StatusValid, records[0]["evatr_status"])
+		assert.Equal(t, float64(1), records[0]["attempt"])
+		assert.Contains(t, records[0], "duration")
+		assert.NotContains(t, records[0], "request_body")
+
+		assert.Equal(t, "WARN", records[1]["level"])
+		assert.EqualValues(t, evatr.StatusVATIDNotAssigned, records[1]["evatr_status"])
+		assert.NotContains(t, buf.String(), "ATU99999999")
+	})
+
+	t.Run("redacted bodies", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		client := srv.Client(evatr.WithLogger(logger, evatr.LoggingOptions{Level: slog.LevelInfo, LogBodies: true}))
+		_, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Geheim GmbH", "Wien", "", "")
+		require.NoError(t, err)
+
+		records := logRecords(t, &buf)
+		require.Len(t, records, 1)
+		assert.Contains(t, records[0]["request_body"], `"angefragteUstid":"AT***"`)
+		assert.Contains(t, records[0]["response_body"], `"ergFirmenname":"A"`)
+		assert.NotContains(t, buf.String(), "12345678")
+		assert.NotContains(t, buf.String(), "Geheim")
+	})
+
+	t.Run("unredacted bodies", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		client := srv.Client(evatr.WithLogger(logger, evatr.LoggingOptions{Level: slog.LevelInfo, LogBodies: true, Unredacted: true}))
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "ATU12345678")
+	})
+
+	t.Run("bodies by endpoint", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		client := srv.Client(evatr.WithLogger(logger, evatr.LoggingOptions{Level: slog.LevelInfo, LogBodies: true}))
+		_, err := client.GetEUMemberStates(t.Context())
+		require.NoError(t, err)
+
+		records := logRecords(t, &buf)
+		require.Len(t, records, 1)
+		assert.Contains(t, records[0]["response_body"], `"alpha2":"AT"`)
+
+		// Nothing is known about other endpoints, even if they answer with a list.
+		buf.Reset()
+		transport := evatr.NewLoggingTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`["ATU12345678"]`))}, nil
+		}), logger, evatr.LoggingOptions{Level: slog.LevelInfo, LogBodies: true})
+		req := httptest.NewRequest("GET", "https://example.com/v1/kunden", nil)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		records = logRecords(t, &buf)
+		require.Len(t, records, 1)
+		assert.Equal(t, "[redacted]", records[0]["response_body"])
+	})
+
+	t.Run("large responses are not buffered", func(t *testing.T) {
+		body := &countingReader{Reader: bytes.NewReader(bytes.Repeat([]byte("x"), 1<<20))}
+		transport := evatr.NewLoggingTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(body)}, nil
+		}), slog.New(slog.DiscardHandler), evatr.LoggingOptions{})
+
+		resp, err := transport.RoundTrip(httptest.NewRequest("GET", "https://example.com/v1/info/eu_mitgliedstaaten", nil))
+		require.NoError(t, err)
+		assert.Less(t, body.n, 1<<16)
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Len(t, data, 1<<20)
+	})
+
+	t.Run("attempt numbers", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		client := srv.Client(
+			evatr.WithLogger(logger, evatr.LoggingOptions{}),
+			evatr.WithRetryPolicy(evatr.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		)
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU55555555")
+		require.Error(t, err)
+
+		records := logRecords(t, &buf)
+		require.Len(t, records, 2)
+		assert.Equal(t, float64(1), records[0]["attempt"])
+		assert.Equal(t, float64(2), records[1]["attempt"])
+	})
+}
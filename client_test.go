@@ -2,12 +2,14 @@ package evatr_test
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,7 +55,7 @@ func TestValidateVAT(t *testing.T) {
 			assert.Equal(t, "ATU12345678", req.RequestedVATID)
 
 			resp := evatr.ValidationResponse{
-				RequestTimestamp: time.Now().Format(time.RFC3339),
+				RequestTimestamp: evatr.Time{Time: time.Now()},
 				Status:           evatr.StatusValid,
 			}
 			w.Header().Set("Content-Type", "application/json")
@@ -72,13 +74,19 @@ func TestValidateVAT(t *testing.T) {
 	t.Run("missing requesting VAT ID", func(t *testing.T) {
 		client := evatr.NewClient()
 		_, err := client.ValidateVAT(t.Context(), "", "ATU12345678")
-		require.Error(t, err)
+		require.ErrorIs(t, err, evatr.ErrMissingRequestingVATID)
 	})
 
 	t.Run("missing requested VAT ID", func(t *testing.T) {
 		client := evatr.NewClient()
 		_, err := client.ValidateVAT(t.Context(), "DE123456789", "")
-		require.Error(t, err)
+		require.ErrorIs(t, err, evatr.ErrMissingRequestedVATID)
+	})
+
+	t.Run("non-German requesting VAT ID", func(t *testing.T) {
+		client := evatr.NewClient()
+		_, err := client.ValidateVAT(t.Context(), "ATU12345678", "DE123456789")
+		require.ErrorIs(t, err, evatr.ErrRequestingVATIDNotGerman)
 	})
 
 	t.Run("VAT ID not found", func(t *testing.T) {
@@ -103,6 +111,82 @@ func TestValidateVAT(t *testing.T) {
 	})
 }
 
+// TestValidateVATID tests validation with parsed and unnormalized VAT IDs
+func TestValidateVATID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req evatr.ValidationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		assert.Equal(t, "DE136695976", req.RequestingVATID)
+		assert.Equal(t, "ATU13585627", req.RequestedVATID)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{
+			RequestTimestamp: evatr.Time{Time: time.Now()},
+			Status:           evatr.StatusValid,
+		})
+	}))
+	defer server.Close()
+
+	client := evatr.NewClient(evatr.WithBaseURL(server.URL))
+
+	t.Run("parsed VAT IDs", func(t *testing.T) {
+		_, err := client.ValidateVATID(t.Context(), vatid.MustParse("DE136695976"), vatid.MustParse("atu 1358 5627"))
+		require.NoError(t, err)
+	})
+
+	t.Run("unnormalized strings", func(t *testing.T) {
+		_, err := client.ValidateVAT(t.Context(), "de 136.695.976", "ATU-13585627")
+		require.NoError(t, err)
+	})
+
+	t.Run("request is not modified", func(t *testing.T) {
+		req := &evatr.ValidationRequest{
+			RequestingVATID: "de136695976",
+			RequestedVATID:  "atu13585627",
+		}
+		_, err := client.ValidateVATWithRequest(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "de136695976", req.RequestingVATID)
+	})
+}
+
+// TestValidateVATPreflight tests the offline pre-flight validation
+func TestValidateVATPreflight(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{
+			RequestTimestamp: evatr.Time{Time: time.Now()},
+			Status:           evatr.StatusValid,
+		})
+	}))
+	defer server.Close()
+
+	client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithPreflight())
+
+	t.Run("invalid checksum never reaches the API", func(t *testing.T) {
+		_, err := client.ValidateVAT(t.Context(), "DE136695976", "ATU13585626")
+		require.Error(t, err)
+
+		var vatErr *vatid.Error
+		require.True(t, errors.As(err, &vatErr))
+		assert.Equal(t, vatid.RuleChecksum, vatErr.Rule)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("valid IDs are sent", func(t *testing.T) {
+		result, err := client.ValidateVATWithRequest(t.Context(), &evatr.ValidationRequest{
+			RequestingVATID: "DE136695976",
+			RequestedVATID:  "ATU13585627",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsValid())
+		assert.Equal(t, 1, calls)
+	})
+}
+
 // TestValidateVATQualified tests qualified VAT ID validation
 func TestValidateVATQualified(t *testing.T) {
 	t.Run("successful qualified validation", func(t *testing.T) {
@@ -114,7 +198,7 @@ func TestValidateVATQualified(t *testing.T) {
 			assert.NotEmpty(t, req.City)
 
 			resp := evatr.ValidationResponse{
-				RequestTimestamp:  time.Now().Format(time.RFC3339),
+				RequestTimestamp:  evatr.Time{Time: time.Now()},
 				Status:            evatr.StatusValid,
 				CompanyNameResult: evatr.VerificationMatch,
 				CityResult:        evatr.VerificationMatch,
@@ -145,19 +229,19 @@ func TestValidateVATQualified(t *testing.T) {
 	t.Run("missing company name", func(t *testing.T) {
 		client := evatr.NewClient()
 		_, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "", "Berlin", "", "")
-		require.Error(t, err)
+		require.ErrorIs(t, err, evatr.ErrMissingCompanyName)
 	})
 
 	t.Run("missing city", func(t *testing.T) {
 		client := evatr.NewClient()
 		_, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Test GmbH", "", "", "")
-		require.Error(t, err)
+		require.ErrorIs(t, err, evatr.ErrMissingCity)
 	})
 
 	t.Run("data mismatch", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			resp := evatr.ValidationResponse{
-				RequestTimestamp:  time.Now().Format(time.RFC3339),
+				RequestTimestamp:  evatr.Time{Time: time.Now()},
 				Status:            evatr.StatusValid,
 				CompanyNameResult: evatr.VerificationMismatch,
 				CityResult:        evatr.VerificationMatch,
@@ -248,7 +332,7 @@ func TestErrorHandling(t *testing.T) {
 		name           string
 		statusCode     int
 		responseBody   evatr.ErrorResponse
-		expectedStatus string
+		expectedStatus evatr.StatusCode
 		expectedCode   int
 	}{
 		{
@@ -308,7 +392,7 @@ func TestErrorHandling(t *testing.T) {
 func TestValidationResponseMethods(t *testing.T) {
 	t.Run("IsValid", func(t *testing.T) {
 		tests := []struct {
-			status   string
+			status   evatr.StatusCode
 			expected bool
 		}{
 			{evatr.StatusValid, true},
@@ -327,7 +411,7 @@ func TestValidationResponseMethods(t *testing.T) {
 	t.Run("GetRequestTimestamp", func(t *testing.T) {
 		now := time.Now()
 		resp := &evatr.ValidationResponse{
-			RequestTimestamp: now.Format(time.RFC3339),
+			RequestTimestamp: evatr.Time{Time: now},
 		}
 		parsed, err := resp.GetRequestTimestamp()
 		require.NoError(t, err)
@@ -337,7 +421,7 @@ func TestValidationResponseMethods(t *testing.T) {
 	t.Run("GetValidFrom with value", func(t *testing.T) {
 		date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		resp := &evatr.ValidationResponse{
-			ValidFrom: date.Format(time.RFC3339),
+			ValidFrom: evatr.Time{Time: date, DateOnly: true},
 		}
 		parsed, err := resp.GetValidFrom()
 		require.NoError(t, err)
@@ -350,4 +434,10 @@ func TestValidationResponseMethods(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, parsed.IsZero())
 	})
+
+	t.Run("GetValidUntil empty", func(t *testing.T) {
+		parsed, err := (&evatr.ValidationResponse{}).GetValidUntil()
+		require.NoError(t, err)
+		assert.True(t, parsed.IsZero())
+	})
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// batch validates the VAT IDs read from a CSV file. Each record holds the
+// requested VAT ID, optionally followed by company name, city, street and
+// postal code for a qualified validation. Lines starting with # are ignored.
+// The input is checked completely before the first request is sent.
+//
+// The output is CSV, or one JSON object per line with -json. The exit code
+// is the "worst" outcome: interruption before other errors before
+// configuration errors before transient failures before invalid VAT IDs
+// before valid ones.
+func (a *app) batch(ctx context.Context, args []string) int {
+	fs, requesting := a.commandFlags("batch", "[flags] <file|->")
+	concurrency := fs.Int("concurrency", evatr.DefaultBatchConcurrency, "number of concurrent requests")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var in io.Reader = a.stdin
+	if name := fs.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+			return exitUsage
+		}
+		defer f.Close()
+		in = f
+	}
+
+	requests, err := readBatch(in, *requesting, a.preflight)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitUsage
+	}
+
+	results, err := a.client.ValidateBatch(ctx, requests, *concurrency)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+	}
+
+	code := exitValid
+	for _, result := range results {
+		code = max(code, exitCode(result.Response, result.Err))
+	}
+	if err := a.writeBatch(results); err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitError
+	}
+	return code
+}
+
+// writeBatch prints the results as CSV or JSON lines.
+func (a *app) writeBatch(results []evatr.BatchResult) error {
+	if a.json {
+		enc := json.NewEncoder(a.stdout)
+		for _, result := range results {
+			if err := enc.Encode(newJSONResult(result.Request.RequestedVATID, result.Response, result.Err)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := csv.NewWriter(a.stdout)
+	if err := w.Write([]string{"vat_id", "valid", "status", "error"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		var valid, errMsg string
+		var status evatr.StatusCode
+		if result.Err != nil {
+			valid = "false"
+			errMsg = result.Err.Error()
+			var apiErr *evatr.Error
+			if errors.As(result.Err, &apiErr) {
+				status = apiErr.Status
+			}
+		} else {
+			valid = fmt.Sprint(result.Response.IsValid())
+			status = result.Response.Status
+		}
+		if err := w.Write([]string{result.Request.RequestedVATID, valid, string(status), errMsg}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// readBatch parses the CSV input into validation requests. It applies the
+// checks of the validate and qualified commands, so that a batch does not
+// stop halfway because of a usage error.
+func readBatch(in io.Reader, requesting string, preflight bool) ([]evatr.ValidationRequest, error) {
+	requesting = vatid.Normalize(requesting)
+	if requesting == "" {
+		return nil, evatr.ErrMissingRequestingVATID
+	}
+	if !strings.HasPrefix(requesting, "DE") {
+		return nil, evatr.ErrRequestingVATIDNotGerman
+	}
+	if preflight {
+		if err := vatid.Validate(requesting); err != nil {
+			return nil, err
+		}
+	}
+
+	r := csv.NewReader(in)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var requests []evatr.ValidationRequest
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read batch file: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) > 5 {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("line %d: expected at most 5 fields, got %d", line, len(record))
+		}
+
+		fields := make([]string, 5)
+		copy(fields, record)
+		if qualified := strings.Join(fields[1:], "") != ""; qualified {
+			line, _ := r.FieldPos(0)
+			if fields[1] == "" {
+				return nil, fmt.Errorf("line %d: %w", line, evatr.ErrMissingCompanyName)
+			}
+			if fields[2] == "" {
+				return nil, fmt.Errorf("line %d: %w", line, evatr.ErrMissingCity)
+			}
+		}
+		requests = append(requests, evatr.ValidationRequest{
+			RequestingVATID: requesting,
+			RequestedVATID:  fields[0],
+			CompanyName:     fields[1],
+			City:            fields[2],
+			Street:          fields[3],
+			PostalCode:      fields[4],
+		})
+	}
+	return requests, nil
+}
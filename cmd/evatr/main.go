@@ -0,0 +1,401 @@
+// Command evatr validates VAT IDs against the eVatR API of the BZSt.
+//
+// Usage:
+//
+//	evatr [flags] <command> [arguments]
+//
+// Commands:
+//
+//	validate         simple validation of a VAT ID
+//	qualified        validation including company data
+//	batch            validate all VAT IDs listed in a CSV file
+//	status-messages  list all eVatR status messages
+//	member-states    list EU member states and their VIES availability
+//
+// Exit codes:
+//
+//	0  valid (or the command succeeded)
+//	1  invalid
+//	2  transient failure, try again later
+//	3  usage or configuration error, e.g. our own VAT ID is not authorized
+//	4  other error that says nothing about the VAT ID, e.g. the session
+//	   limit of qualified requests or an unexpected answer
+//	130  interrupted
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+const (
+	exitValid     = 0
+	exitInvalid   = 1
+	exitTransient = 2
+	exitUsage     = 3
+	exitError     = 4
+	exitCanceled  = 130
+)
+
+// requestingEnv is the environment variable for the default requesting VAT ID.
+const requestingEnv = "EVATR_REQUESTING_VATID"
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// app holds the global flags and output streams shared by all commands.
+type app struct {
+	client    *evatr.Client
+	json      bool
+	preflight bool
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("evatr", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: evatr [flags] <validate|qualified|batch|status-messages|member-states> [arguments]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	baseURL := fs.String("base-url", evatr.DefaultBaseURL, "base URL of the eVatR API")
+	timeout := fs.Duration("timeout", evatr.DefaultTimeout, "timeout for HTTP requests")
+	debug := fs.Bool("debug", false, "dump HTTP requests and responses")
+	retries := fs.Int("retries", 1, "maximum attempts for transient failures")
+	preflight := fs.Bool("preflight", true, "validate VAT ID syntax and checksum before calling the API")
+	jsonOutput := fs.Bool("json", false, "print JSON instead of text")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	httpClient := &http.Client{Timeout: *timeout}
+	if *debug {
+		httpClient.Transport = evatr.NewDebugTransport(nil)
+	}
+
+	opts := []evatr.Option{
+		evatr.WithBaseURL(*baseURL),
+		evatr.WithHTTPClient(httpClient),
+	}
+	if *retries > 1 {
+		policy := evatr.DefaultRetryPolicy
+		policy.MaxAttempts = *retries
+		opts = append(opts, evatr.WithRetryPolicy(policy))
+	}
+	if *preflight {
+		opts = append(opts, evatr.WithPreflight())
+	}
+
+	a := &app{
+		client:    evatr.NewClient(opts...),
+		json:      *jsonOutput,
+		preflight: *preflight,
+		stdin:     stdin,
+		stdout:    stdout,
+		stderr:    stderr,
+	}
+
+	command, commandArgs := fs.Arg(0), fs.Args()[1:]
+	switch command {
+	case "validate":
+		return a.validate(ctx, commandArgs)
+	case "qualified":
+		return a.qualified(ctx, commandArgs)
+	case "batch":
+		return a.batch(ctx, commandArgs)
+	case "status-messages":
+		return a.statusMessages(ctx, commandArgs)
+	case "member-states":
+		return a.memberStates(ctx, commandArgs)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", command)
+		fs.Usage()
+		return exitUsage
+	}
+}
+
+// exitCode maps the outcome of a validation to an exit code.
+func exitCode(resp *evatr.ValidationResponse, err error) int {
+	if err == nil {
+		if resp.IsValid() {
+			return exitValid
+		}
+		return exitInvalid
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitCanceled
+	case evatr.IsRetryable(err):
+		return exitTransient
+	case evatr.IsConfigurationFault(err):
+		return exitUsage
+	case isInvalid(err):
+		return exitInvalid
+	}
+	return exitError
+}
+
+// isInvalid returns whether err says the requested VAT ID is invalid: the
+// API rejected or does not know it, or the pre-flight check found it
+// malformed.
+func isInvalid(err error) bool {
+	if !evatr.IsCallerFault(err) {
+		return false
+	}
+	var apiErr *evatr.Error
+	var vatErr *vatid.Error
+	return errors.As(err, &apiErr) || errors.As(err, &vatErr)
+}
+
+// isUsageError returns whether err was caused by invalid arguments rather
+// than by the requested VAT ID.
+func isUsageError(err error, requesting string) bool {
+	for _, target := range []error{
+		evatr.ErrMissingRequestingVATID,
+		evatr.ErrRequestingVATIDNotGerman,
+		evatr.ErrMissingRequestedVATID,
+		evatr.ErrMissingCompanyName,
+		evatr.ErrMissingCity,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	// A malformed requested VAT ID caught by the pre-flight check is invalid.
+	var vatErr *vatid.Error
+	return errors.As(err, &vatErr) && vatErr.VATID == vatid.Normalize(requesting)
+}
+
+func (a *app) printJSON(v any) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// jsonResult is the JSON output of a validation.
+type jsonResult struct {
+	VATID    string                    `json:"vatId"`
+	Valid    bool                      `json:"valid"`
+	Response *evatr.ValidationResponse `json:"response,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+func newJSONResult(vatID string, resp *evatr.ValidationResponse, err error) jsonResult {
+	out := jsonResult{VATID: vatID, Response: resp}
+	if err != nil {
+		out.Error = err.Error()
+	} else {
+		out.Valid = resp.IsValid()
+	}
+	return out
+}
+
+// printResult prints the outcome of a validation. It only fails if the
+// output cannot be written.
+func (a *app) printResult(vatID string, resp *evatr.ValidationResponse, err error) error {
+	if a.json {
+		return a.printJSON(newJSONResult(vatID, resp, err))
+	}
+
+	if err != nil {
+		_, err = fmt.Fprintf(a.stdout, "%s: error: %v\n", vatID, err)
+		return err
+	}
+
+	state := "invalid"
+	if resp.IsValid() {
+		state = "valid"
+	}
+	if _, err := fmt.Fprintf(a.stdout, "%s: %s (%s)\n", vatID, state, resp.Status); err != nil {
+		return err
+	}
+	if !resp.ValidFrom.IsZero() {
+		fmt.Fprintf(a.stdout, "  valid from:  %s\n", resp.ValidFrom)
+	}
+	if !resp.ValidUntil.IsZero() {
+		fmt.Fprintf(a.stdout, "  valid until: %s\n", resp.ValidUntil)
+	}
+	printVerification(a.stdout, "company name", resp.CompanyNameResult)
+	printVerification(a.stdout, "street", resp.StreetResult)
+	printVerification(a.stdout, "postal code", resp.PostalCodeResult)
+	printVerification(a.stdout, "city", resp.CityResult)
+
+	if summary := resp.Summary(); summary.State != evatr.MatchNotRequested {
+		msg := summary.State.String()
+		if summary.Confirmed {
+			msg += ", confirmed"
+		}
+		fmt.Fprintf(a.stdout, "  %-13s %s\n", "company data:", msg)
+	}
+	return nil
+}
+
+func printVerification(w io.Writer, field string, result evatr.VerificationResult) {
+	var msg string
+	switch result {
+	case "":
+		return
+	case evatr.VerificationMatch:
+		msg = "matches"
+	case evatr.VerificationMismatch:
+		msg = "does not match"
+	case evatr.VerificationNotRequested:
+		msg = "not requested"
+	case evatr.VerificationNotProvided:
+		msg = "not provided by member state"
+	default:
+		msg = string(result)
+	}
+	fmt.Fprintf(w, "  %-13s %s\n", field+":", msg)
+}
+
+// commandFlags returns a flag set for a command with the -requesting flag.
+func (a *app) commandFlags(name, usage string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: evatr %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	requesting := fs.String("requesting", os.Getenv(requestingEnv), "your German VAT ID (default $"+requestingEnv+")")
+	return fs, requesting
+}
+
+func (a *app) validate(ctx context.Context, args []string) int {
+	fs, requesting := a.commandFlags("validate", "[flags] <vat-id>")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	resp, err := a.client.ValidateVAT(ctx, *requesting, fs.Arg(0))
+	if isUsageError(err, *requesting) {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitUsage
+	}
+	if err := a.printResult(fs.Arg(0), resp, err); err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitError
+	}
+	return exitCode(resp, err)
+}
+
+func (a *app) qualified(ctx context.Context, args []string) int {
+	fs, requesting := a.commandFlags("qualified", "[flags] <vat-id>")
+	company := fs.String("company", "", "company name (required)")
+	city := fs.String("city", "", "city (required)")
+	street := fs.String("street", "", "street")
+	postalCode := fs.String("postal-code", "", "postal code")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	resp, err := a.client.ValidateVATQualified(ctx, *requesting, fs.Arg(0), *company, *city, *street, *postalCode)
+	if isUsageError(err, *requesting) {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitUsage
+	}
+	if err := a.printResult(fs.Arg(0), resp, err); err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return exitError
+	}
+	return exitCode(resp, err)
+}
+
+func (a *app) statusMessages(ctx context.Context, args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(a.stderr, "Usage: evatr status-messages")
+		return exitUsage
+	}
+
+	messages, err := a.client.GetStatusMessages(ctx)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return failureCode(err)
+	}
+
+	if a.json {
+		if err := a.printJSON(messages); err != nil {
+			fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+			return exitError
+		}
+		return exitValid
+	}
+	for _, msg := range messages {
+		fmt.Fprintf(a.stdout, "%s\t%d\t%s\t%s\n", msg.Status, msg.HTTPCode, msg.Category, msg.Message)
+	}
+	return exitValid
+}
+
+func (a *app) memberStates(ctx context.Context, args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(a.stderr, "Usage: evatr member-states")
+		return exitUsage
+	}
+
+	states, err := a.client.GetEUMemberStates(ctx)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+		return failureCode(err)
+	}
+
+	if a.json {
+		if err := a.printJSON(states); err != nil {
+			fmt.Fprintf(a.stderr, "evatr: %v\n", err)
+			return exitError
+		}
+		return exitValid
+	}
+	for _, state := range states {
+		status := "available"
+		if !state.Available {
+			status = "unavailable"
+		}
+		fmt.Fprintf(a.stdout, "%s\t%s\t%s\n", state.Alpha2, status, state.Name)
+	}
+	return exitValid
+}
+
+// failureCode returns the exit code for a failed info request.
+func failureCode(err error) int {
+	if errors.Is(err, context.Canceled) {
+		return exitCanceled
+	}
+	switch {
+	case evatr.IsRetryable(err):
+		return exitTransient
+	case evatr.IsConfigurationFault(err):
+		return exitUsage
+	}
+	return exitError
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/info/eu_mitgliedstaaten":
+			json.NewEncoder(w).Encode([]evatr.EUMemberState{{Alpha2: "AT", Name: "Österreich", Available: true}})
+			return
+		case "/v1/info/statusmeldungen":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable1})
+			return
+		}
+
+		var req evatr.ValidationRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.RequestingVATID == "DE999999999" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusNotAuthorizedDE})
+			return
+		}
+		switch req.RequestedVATID {
+		case "ATU99999999":
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusMaxQualifiedRequestsReached})
+		case "ATU88888888":
+			w.Write([]byte("{"))
+		case "ATU13585627":
+			json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+		case "DK13585628":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusVATIDNotAssigned})
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable2})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// TestRun tests commands and exit codes
+func TestRun(t *testing.T) {
+	server := testServer(t)
+
+	testCases := []struct {
+		name  string
+		args  []string
+		stdin string
+		code  int
+	}{
+		{"no command", nil, "", exitUsage},
+		{"unknown command", []string{"frobnicate"}, "", exitUsage},
+		{"valid", []string{"validate", "-requesting", "DE136695976", "ATU13585627"}, "", exitValid},
+		{"not assigned", []string{"validate", "-requesting", "DE136695976", "DK13585628"}, "", exitInvalid},
+		{"malformed", []string{"validate", "-requesting", "DE136695976", "ATU13585626"}, "", exitInvalid},
+		{"transient", []string{"validate", "-requesting", "DE136695976", "SI50223054"}, "", exitTransient},
+		{"session limit", []string{"-preflight=false", "validate", "-requesting", "DE136695976", "ATU99999999"}, "", exitError},
+		{"malformed answer", []string{"-preflight=false", "validate", "-requesting", "DE136695976", "ATU88888888"}, "", exitError},
+		{"missing requesting", []string{"validate", "ATU13585627"}, "", exitUsage},
+		{"not authorized", []string{"-preflight=false", "validate", "-requesting", "DE999999999", "ATU13585627"}, "", exitUsage},
+		{"qualified without company", []string{"qualified", "-requesting", "DE136695976", "ATU13585627"}, "", exitUsage},
+		{"qualified", []string{"-json", "qualified", "-requesting", "DE136695976", "-company", "Test", "-city", "Wien", "ATU13585627"}, "", exitValid},
+		{"member states", []string{"member-states"}, "", exitValid},
+		{"status messages unavailable", []string{"status-messages"}, "", exitTransient},
+		{"batch valid", []string{"batch", "-requesting", "DE136695976", "-"}, "# customers\nATU13585627\nATU13585627,Test,Wien\n", exitValid},
+		{"batch invalid", []string{"batch", "-requesting", "DE136695976", "-"}, "ATU13585627\nDK13585628\n", exitInvalid},
+		{"batch transient", []string{"batch", "-requesting", "DE136695976", "-"}, "DK13585628\nSI50223054\n", exitTransient},
+		{"batch not authorized", []string{"-preflight=false", "batch", "-requesting", "DE999999999", "-"}, "ATU13585627\n", exitUsage},
+		{"batch not german", []string{"batch", "-requesting", "ATU13585627", "-"}, "ATU13585627\n", exitUsage},
+		{"batch malformed requesting", []string{"batch", "-requesting", "DE136695975", "-"}, "ATU13585627\n", exitUsage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(requestingEnv, "")
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-base-url", server.URL}, tc.args...)
+			code := run(t.Context(), args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			assert.Equal(t, tc.code, code, "stdout: %s\nstderr: %s", stdout.String(), stderr.String())
+		})
+	}
+}
+
+// TestRunBatchInput tests a bad row stops the batch before any request
+func TestRunBatchInput(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-base-url", server.URL, "batch", "-requesting", "DE136695976", "-"}
+	code := run(t.Context(), args, strings.NewReader("ATU13585627\nATU13585627,Test\n"), &stdout, &stderr)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "line 2: "+evatr.ErrMissingCity.Error())
+	assert.Empty(t, stdout.String())
+	assert.Zero(t, requests)
+}
+
+// TestRunBatchJSON tests batch prints one JSON object per line
+func TestRunBatchJSON(t *testing.T) {
+	server := testServer(t)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-base-url", server.URL, "-json", "batch", "-requesting", "DE136695976", "-"}
+	code := run(t.Context(), args, strings.NewReader("ATU13585627\nDK13585628\n"), &stdout, &stderr)
+	assert.Equal(t, exitInvalid, code, "stderr: %s", stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	var results []map[string]any
+	for _, line := range lines {
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &result))
+		results = append(results, result)
+	}
+	assert.Equal(t, "ATU13585627", results[0]["vatId"])
+	assert.Equal(t, true, results[0]["valid"])
+	assert.Equal(t, false, results[1]["valid"])
+	assert.NotEmpty(t, results[1]["error"])
+}
+
+// TestRunBatchCanceled tests an interrupted batch prints the finished rows
+func TestRunBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req evatr.ValidationRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.RequestedVATID != "ATU13585627" {
+			io.Copy(io.Discard, r.Body)
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-base-url", server.URL, "batch", "-requesting", "DE136695976", "-concurrency", "1", "-"}
+	code := run(ctx, args, strings.NewReader("ATU13585627\nDK13585628\nSI50223054\n"), &stdout, &stderr)
+	assert.Equal(t, exitCanceled, code, "stderr: %s", stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "ATU13585627,true,evatr-0000,", lines[1])
+	assert.Contains(t, lines[2], "context canceled")
+}
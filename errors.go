@@ -1,11 +1,25 @@
 package evatr
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by the client for invalid input, before any request is sent.
+var (
+	ErrMissingRequest           = errors.New("evatr: request is required")
+	ErrMissingRequestingVATID   = errors.New("evatr: requesting VAT ID is required")
+	ErrRequestingVATIDNotGerman = errors.New("evatr: requesting VAT ID must be German")
+	ErrMissingRequestedVATID    = errors.New("evatr: requested VAT ID is required")
+	ErrMissingCompanyName       = errors.New("evatr: company name is required for qualified validation")
+	ErrMissingCity              = errors.New("evatr: city is required for qualified validation")
+)
 
 // ErrorResponse represents the JSON error response from the API.
 type ErrorResponse struct {
 	// eVATR status code (e.g., "evatr-0002")
-	Status string `json:"status"`
+	Status StatusCode `json:"status"`
 
 	// Error message in German
 	Message string `json:"meldung"`
@@ -17,10 +31,13 @@ type Error struct {
 	StatusCode int
 
 	// eVATR status code (e.g., "evatr-0002")
-	Status string
+	Status StatusCode
 
 	// Human-readable error message
 	Message string
+
+	// Delay requested by the server via the Retry-After header
+	retryAfter time.Duration
 }
 
 func (e *Error) Error() string {
@@ -30,19 +47,41 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("evatr: HTTP %d: %s", e.StatusCode, e.Message)
 }
 
-// IsEvatrErr returns whether the error is an eVATR error.
-func IsEvatrErr(err error) bool {
-	if err == nil {
+// Is reports whether target is an *Error with the same eVatR status, so
+// errors can be matched against status constants:
+//
+//	if errors.Is(err, evatr.StatusError(evatr.StatusVATIDNotAssigned)) {
+//		...
+//	}
+//
+// A target without status matches by HTTP status code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
 		return false
 	}
-	_, ok := err.(*Error)
-	return ok
+	if t.Status != "" {
+		return e.Status == t.Status
+	}
+	return t.StatusCode != 0 && e.StatusCode == t.StatusCode
+}
+
+// StatusError returns an error matching every API error with the given
+// eVatR status in errors.Is.
+func StatusError(status StatusCode) error {
+	return &Error{Status: status}
+}
+
+// IsEvatrErr returns whether the error is or wraps an eVATR error.
+func IsEvatrErr(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr)
 }
 
 // Common error constructors based on status codes from the API
 
 // NewBadRequestError returns a 400 Bad Request error.
-func NewBadRequestError(status, message string) *Error {
+func NewBadRequestError(status StatusCode, message string) *Error {
 	return &Error{
 		StatusCode: 400,
 		Status:     status,
@@ -51,7 +90,7 @@ func NewBadRequestError(status, message string) *Error {
 }
 
 // NewForbiddenError returns a 403 Forbidden error.
-func NewForbiddenError(status, message string) *Error {
+func NewForbiddenError(status StatusCode, message string) *Error {
 	return &Error{
 		StatusCode: 403,
 		Status:     status,
@@ -60,7 +99,7 @@ func NewForbiddenError(status, message string) *Error {
 }
 
 // NewNotFoundError returns a 404 Not Found error.
-func NewNotFoundError(status, message string) *Error {
+func NewNotFoundError(status StatusCode, message string) *Error {
 	return &Error{
 		StatusCode: 404,
 		Status:     status,
@@ -69,7 +108,7 @@ func NewNotFoundError(status, message string) *Error {
 }
 
 // NewInternalServerError returns a 500 Internal Server Error.
-func NewInternalServerError(status, message string) *Error {
+func NewInternalServerError(status StatusCode, message string) *Error {
 	return &Error{
 		StatusCode: 500,
 		Status:     status,
@@ -78,7 +117,7 @@ func NewInternalServerError(status, message string) *Error {
 }
 
 // NewServiceUnavailableError returns a 503 Service Unavailable error.
-func NewServiceUnavailableError(status, message string) *Error {
+func NewServiceUnavailableError(status StatusCode, message string) *Error {
 	return &Error{
 		StatusCode: 503,
 		Status:     status,
@@ -90,46 +129,46 @@ func NewServiceUnavailableError(status, message string) *Error {
 
 // Bad Request (400) errors
 const (
-	StatusMissingRequiredField        = "evatr-0002" // At least one required field is missing
-	StatusInvalidRequestingVATID      = "evatr-0004" // Requesting DE VAT ID is syntactically incorrect
-	StatusInvalidRequestedVATID       = "evatr-0005" // Requested VAT ID is syntactically incorrect
-	StatusMaxQualifiedRequestsReached = "evatr-0008" // Maximum qualified requests for session reached
-	StatusInvalidVATIDFormat          = "evatr-0012" // Requested VAT ID doesn't match format
-	StatusInvalidCountryCode          = "evatr-2003" // Country code is not valid
+	StatusMissingRequiredField        StatusCode = "evatr-0002" // At least one required field is missing
+	StatusInvalidRequestingVATID      StatusCode = "evatr-0004" // Requesting DE VAT ID is syntactically incorrect
+	StatusInvalidRequestedVATID       StatusCode = "evatr-0005" // Requested VAT ID is syntactically incorrect
+	StatusMaxQualifiedRequestsReached StatusCode = "evatr-0008" // Maximum qualified requests for session reached
+	StatusInvalidVATIDFormat          StatusCode = "evatr-0012" // Requested VAT ID doesn't match format
+	StatusInvalidCountryCode          StatusCode = "evatr-2003" // Country code is not valid
 )
 
 // Forbidden (403) errors
 const (
-	StatusNotAuthorizedDE = "evatr-0006" // Requesting DE VAT ID not authorized to query DE VAT IDs
-	StatusInvalidCall     = "evatr-0007" // Invalid call
+	StatusNotAuthorizedDE StatusCode = "evatr-0006" // Requesting DE VAT ID not authorized to query DE VAT IDs
+	StatusInvalidCall     StatusCode = "evatr-0007" // Invalid call
 )
 
 // Not Found (404) errors
 const (
-	StatusVATIDNotAssigned        = "evatr-2001" // VAT ID not assigned at request time
-	StatusRequestingVATIDNotValid = "evatr-2005" // Requesting DE VAT ID not valid at request time
+	StatusVATIDNotAssigned        StatusCode = "evatr-2001" // VAT ID not assigned at request time
+	StatusRequestingVATIDNotValid StatusCode = "evatr-2005" // Requesting DE VAT ID not valid at request time
 )
 
 // Success (200) with special meanings
 const (
-	StatusValid                = "evatr-0000" // VAT ID is valid at request time
-	StatusNotYetValid          = "evatr-2002" // VAT ID not yet valid, see gueltigAb
-	StatusNoLongerValid        = "evatr-2006" // VAT ID no longer valid, see gueltigAb and gueltigBis
-	StatusValidWithSpecialCase = "evatr-2008" // VAT ID valid but special case, contact BZSt
+	StatusValid                StatusCode = "evatr-0000" // VAT ID is valid at request time
+	StatusNotYetValid          StatusCode = "evatr-2002" // VAT ID not yet valid, see gueltigAb
+	StatusNoLongerValid        StatusCode = "evatr-2006" // VAT ID no longer valid, see gueltigAb and gueltigBis
+	StatusValidWithSpecialCase StatusCode = "evatr-2008" // VAT ID valid but special case, contact BZSt
 )
 
 // Internal Server Error (500) errors
 const (
-	StatusProcessingError1 = "evatr-2004" // Processing temporarily not possible
-	StatusProcessingError2 = "evatr-2011" // Processing temporarily not possible
-	StatusProcessingError3 = "evatr-3011" // Processing temporarily not possible
+	StatusProcessingError1 StatusCode = "evatr-2004" // Processing temporarily not possible
+	StatusProcessingError2 StatusCode = "evatr-2011" // Processing temporarily not possible
+	StatusProcessingError3 StatusCode = "evatr-3011" // Processing temporarily not possible
 )
 
 // Service Unavailable (503) errors
 const (
-	StatusServiceUnavailable1 = "evatr-0011" // Service temporarily unavailable
-	StatusServiceUnavailable2 = "evatr-1001" // Service temporarily unavailable
-	StatusServiceUnavailable3 = "evatr-1002" // Service temporarily unavailable
-	StatusServiceUnavailable4 = "evatr-1003" // Service temporarily unavailable
-	StatusServiceUnavailable5 = "evatr-1004" // Service temporarily unavailable
+	StatusServiceUnavailable1 StatusCode = "evatr-0011" // Service temporarily unavailable
+	StatusServiceUnavailable2 StatusCode = "evatr-1001" // Service temporarily unavailable
+	StatusServiceUnavailable3 StatusCode = "evatr-1002" // Service temporarily unavailable
+	StatusServiceUnavailable4 StatusCode = "evatr-1003" // Service temporarily unavailable
+	StatusServiceUnavailable5 StatusCode = "evatr-1004" // Service temporarily unavailable
 )
@@ -0,0 +1,32 @@
+package evatr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorIs tests matching API errors with errors.Is and errors.As
+func TestErrorIs(t *testing.T) {
+	apiErr := evatr.NewNotFoundError(evatr.StatusVATIDNotAssigned, "nicht vergeben")
+	wrapped := fmt.Errorf("checking customer: %w", apiErr)
+
+	assert.ErrorIs(t, wrapped, evatr.StatusError(evatr.StatusVATIDNotAssigned))
+	assert.ErrorIs(t, wrapped, &evatr.Error{StatusCode: 404})
+	assert.NotErrorIs(t, wrapped, evatr.StatusError(evatr.StatusRequestingVATIDNotValid))
+	assert.NotErrorIs(t, wrapped, &evatr.Error{StatusCode: 500})
+	assert.NotErrorIs(t, wrapped, &evatr.Error{})
+	assert.NotErrorIs(t, wrapped, evatr.ErrMissingRequestedVATID)
+
+	assert.True(t, evatr.IsEvatrErr(wrapped))
+	assert.False(t, evatr.IsEvatrErr(evatr.ErrMissingRequestedVATID))
+	assert.False(t, evatr.IsEvatrErr(nil))
+
+	var target *evatr.Error
+	if assert.True(t, errors.As(wrapped, &target)) {
+		assert.Equal(t, "nicht vergeben", target.Message)
+	}
+}
@@ -0,0 +1,38 @@
+package evatrtest
+
+import "github.com/hostwithquantum/go-evatr"
+
+// DefaultMemberStates are the member states returned by
+// /v1/info/eu_mitgliedstaaten, all available.
+func DefaultMemberStates() []evatr.EUMemberState {
+	return []evatr.EUMemberState{
+		{Alpha2: "AT", Name: "Österreich", Available: true},
+		{Alpha2: "BE", Name: "Belgien", Available: true},
+		{Alpha2: "BG", Name: "Bulgarien", Available: true},
+		{Alpha2: "CY", Name: "Zypern", Available: true},
+		{Alpha2: "CZ", Name: "Tschechien", Available: true},
+		{Alpha2: "DE", Name: "Deutschland", Available: true},
+		{Alpha2: "DK", Name: "Dänemark", Available: true},
+		{Alpha2: "EE", Name: "Estland", Available: true},
+		{Alpha2: "EL", Name: "Griechenland", Available: true},
+		{Alpha2: "ES", Name: "Spanien", Available: true},
+		{Alpha2: "FI", Name: "Finnland", Available: true},
+		{Alpha2: "FR", Name: "Frankreich", Available: true},
+		{Alpha2: "HR", Name: "Kroatien", Available: true},
+		{Alpha2: "HU", Name: "Ungarn", Available: true},
+		{Alpha2: "IE", Name: "Irland", Available: true},
+		{Alpha2: "IT", Name: "Italien", Available: true},
+		{Alpha2: "LT", Name: "Litauen", Available: true},
+		{Alpha2: "LU", Name: "Luxemburg", Available: true},
+		{Alpha2: "LV", Name: "Lettland", Available: true},
+		{Alpha2: "MT", Name: "Malta", Available: true},
+		{Alpha2: "NL", Name: "Niederlande", Available: true},
+		{Alpha2: "PL", Name: "Polen", Available: true},
+		{Alpha2: "PT", Name: "Portugal", Available: true},
+		{Alpha2: "RO", Name: "Rumänien", Available: true},
+		{Alpha2: "SE", Name: "Schweden", Available: true},
+		{Alpha2: "SI", Name: "Slowenien", Available: true},
+		{Alpha2: "SK", Name: "Slowakei", Available: true},
+		{Alpha2: "XI", Name: "Nordirland", Available: true},
+	}
+}
@@ -0,0 +1,364 @@
+// Package evatrtest provides a fake eVatR API for tests.
+//
+// The server implements /v1/abfrage, /v1/info/statusmeldungen and
+// /v1/info/eu_mitgliedstaaten. Answers are configured per requested VAT ID,
+// every request is recorded for assertions:
+//
+//	srv := evatrtest.NewServer()
+//	defer srv.Close()
+//
+//	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+//
+//	client := srv.Client()
+//	_, err := client.ValidateVAT(ctx, "DE123456789", "ATU99999999")
+package evatrtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// Response configures the answer to a validation request.
+type Response struct {
+	// eVatR status, the HTTP status code is derived from it
+	Status evatr.StatusCode
+
+	// Overrides the HTTP status code derived from Status
+	HTTPStatus int
+
+	// Overrides the German message of the status
+	Message string
+
+	// Dates returned verbatim in gueltigAb and gueltigBis, e.g. "2024-12-31"
+	ValidFrom  string
+	ValidUntil string
+
+	// Results of a qualified validation. Unset results default to A for
+	// fields sent in the request and C for fields not sent.
+	CompanyNameResult evatr.VerificationResult
+	StreetResult      evatr.VerificationResult
+	PostalCodeResult  evatr.VerificationResult
+	CityResult        evatr.VerificationResult
+
+	// Additional delay before this answer is sent
+	Latency time.Duration
+}
+
+// RecordedRequest is a request received by the server.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+
+	// Decoded body of a /v1/abfrage request
+	Validation *evatr.ValidationRequest
+}
+
+// Server is a fake eVatR API.
+type Server struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	responses       map[string]Response
+	defaultResponse Response
+	latency         time.Duration
+	memberStates    []evatr.EUMemberState
+	requests        []RecordedRequest
+	now             func() time.Time
+	nextID          int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithDefaultResponse sets the answer for VAT IDs without a configured
+// response. Without it, every VAT ID is valid.
+func WithDefaultResponse(resp Response) Option {
+	return func(s *Server) {
+		s.defaultResponse = resp
+	}
+}
+
+// WithLatency delays every answer.
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithMemberStates sets the member states returned by the server.
+func WithMemberStates(states []evatr.EUMemberState) Option {
+	return func(s *Server) {
+		s.memberStates = states
+	}
+}
+
+// WithClock sets the clock used for anfrageZeitpunkt.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts a fake eVatR API. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		responses:       make(map[string]Response),
+		defaultResponse: Response{Status: evatr.StatusValid},
+		memberStates:    DefaultMemberStates(),
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/abfrage", s.handleValidation)
+	mux.HandleFunc("GET /v1/info/statusmeldungen", s.handleStatusMessages)
+	mux.HandleFunc("GET /v1/info/eu_mitgliedstaaten", s.handleMemberStates)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	return s
+}
+
+// Client returns an evatr.Client talking to the server.
+func (s *Server) Client(opts ...evatr.Option) *evatr.Client {
+	return evatr.NewClient(append([]evatr.Option{evatr.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// SetResponse configures the answer for a requested VAT ID.
+func (s *Server) SetResponse(vatID string, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[vatid.Normalize(vatID)] = resp
+}
+
+// SetLatency changes the delay of every answer.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetAvailable changes the VIES availability of a member state.
+func (s *Server) SetAvailable(alpha2 string, available bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.memberStates {
+		if s.memberStates[i].Alpha2 == alpha2 {
+			s.memberStates[i].Available = available
+		}
+	}
+}
+
+// Requests returns all requests received so far.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// ValidationRequests returns the decoded bodies of all /v1/abfrage requests.
+func (s *Server) ValidationRequests() []evatr.ValidationRequest {
+	var result []evatr.ValidationRequest
+	for _, req := range s.Requests() {
+		if req.Validation != nil {
+			result = append(result, *req.Validation)
+		}
+	}
+	return result
+}
+
+// Reset forgets the recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// record stores every request and applies the global latency.
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		recorded := RecordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header.Clone(),
+			Body:   body,
+			Time:   time.Now(),
+		}
+		if r.URL.Path == "/v1/abfrage" {
+			var req evatr.ValidationRequest
+			if json.Unmarshal(body, &req) == nil {
+				recorded.Validation = &req
+			}
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, recorded)
+		latency := s.latency
+		s.mu.Unlock()
+
+		if !sleep(r, latency) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sleep waits for d unless the client goes away first.
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeStatus(w http.ResponseWriter, status evatr.StatusCode, message string, httpStatus int) {
+	if httpStatus == 0 {
+		httpStatus = status.HTTPCode()
+		if !status.IsKnown() {
+			httpStatus = http.StatusInternalServerError
+		}
+	}
+	if message == "" {
+		message = status.GermanDescription()
+	}
+	writeJSON(w, httpStatus, evatr.ErrorResponse{Status: status, Message: message})
+}
+
+func (s *Server) handleValidation(w http.ResponseWriter, r *http.Request) {
+	var req evatr.ValidationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStatus(w, evatr.StatusInvalidCall, "", 0)
+		return
+	}
+	if req.RequestingVATID == "" || req.RequestedVATID == "" {
+		writeStatus(w, evatr.StatusMissingRequiredField, "", 0)
+		return
+	}
+	if len(req.RequestingVATID) != 11 || req.RequestingVATID[:2] != "DE" {
+		writeStatus(w, evatr.StatusInvalidRequestingVATID, "", 0)
+		return
+	}
+	if !vatid.IsSupported(req.RequestedVATID[:min(2, len(req.RequestedVATID))]) {
+		writeStatus(w, evatr.StatusInvalidCountryCode, "", 0)
+		return
+	}
+
+	s.mu.Lock()
+	resp, ok := s.responses[vatid.Normalize(req.RequestedVATID)]
+	if !ok {
+		resp = s.defaultResponse
+	}
+	s.nextID++
+	id := fmt.Sprintf("evatrtest-%06d", s.nextID)
+	now := s.now()
+	s.mu.Unlock()
+
+	if !sleep(r, resp.Latency) {
+		return
+	}
+
+	code := resp.HTTPStatus
+	if code == 0 {
+		code = http.StatusOK
+		if resp.Status.IsKnown() {
+			code = resp.Status.HTTPCode()
+		}
+	}
+	if code != http.StatusOK {
+		writeStatus(w, resp.Status, resp.Message, code)
+		return
+	}
+
+	answer := validationResponse{
+		ValidationResponse: evatr.ValidationResponse{
+			ID:     id,
+			Status: resp.Status,
+		},
+		RequestTimestamp: now.Format(time.RFC3339),
+		ValidFrom:        resp.ValidFrom,
+		ValidUntil:       resp.ValidUntil,
+	}
+	if req.CompanyName != "" && req.City != "" {
+		answer.CompanyNameResult = result(resp.CompanyNameResult, req.CompanyName)
+		answer.StreetResult = result(resp.StreetResult, req.Street)
+		answer.PostalCodeResult = result(resp.PostalCodeResult, req.PostalCode)
+		answer.CityResult = result(resp.CityResult, req.City)
+	}
+	writeJSON(w, http.StatusOK, answer)
+}
+
+// validationResponse sends the configured dates verbatim, so tests can
+// check how clients deal with unexpected values.
+type validationResponse struct {
+	evatr.ValidationResponse
+	RequestTimestamp string `json:"anfrageZeitpunkt"`
+	ValidFrom        string `json:"gueltigAb,omitempty"`
+	ValidUntil       string `json:"gueltigBis,omitempty"`
+}
+
+// result returns the configured result or the default for the field value.
+func result(configured evatr.VerificationResult, value string) evatr.VerificationResult {
+	if configured != "" {
+		return configured
+	}
+	if value == "" {
+		return evatr.VerificationNotRequested
+	}
+	return evatr.VerificationMatch
+}
+
+func (s *Server) handleStatusMessages(w http.ResponseWriter, r *http.Request) {
+	codes := evatr.StatusCodes()
+	messages := make([]evatr.StatusMessage, 0, len(codes))
+	for _, code := range codes {
+		category := "Fehler"
+		if code.IsResult() {
+			category = "Ergebnis"
+		}
+		messages = append(messages, evatr.StatusMessage{
+			Status:   code,
+			Category: category,
+			HTTPCode: code.HTTPCode(),
+			Field:    code.Field(),
+			Message:  code.GermanDescription(),
+		})
+	}
+	writeJSON(w, http.StatusOK, messages)
+}
+
+func (s *Server) handleMemberStates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	states := append([]evatr.EUMemberState(nil), s.memberStates...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, states)
+}
@@ -0,0 +1,124 @@
+package evatrtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer tests the fake API with the client
+func TestServer(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+
+	t.Run("valid by default", func(t *testing.T) {
+		resp, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.True(t, resp.IsValid())
+		assert.NotEmpty(t, resp.ID)
+
+		_, err = resp.GetRequestTimestamp()
+		assert.NoError(t, err)
+	})
+
+	t.Run("configured answers", func(t *testing.T) {
+		srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+		srv.SetResponse("atu 11111111", evatrtest.Response{
+			Status:     evatr.StatusNoLongerValid,
+			ValidFrom:  "2020-01-01",
+			ValidUntil: "2024-12-31",
+		})
+
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+		require.Error(t, err)
+		apiErr := err.(*evatr.Error)
+		assert.Equal(t, 404, apiErr.StatusCode)
+		assert.Equal(t, evatr.StatusVATIDNotAssigned, apiErr.Status)
+		assert.NotEmpty(t, apiErr.Message)
+
+		resp, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU11111111")
+		require.NoError(t, err)
+		assert.False(t, resp.IsValid())
+		assert.Equal(t, "2024-12-31", resp.ValidUntil.String())
+		assert.True(t, resp.ValidUntil.DateOnly)
+	})
+
+	t.Run("every status", func(t *testing.T) {
+		codes := map[evatr.StatusCode]int{
+			evatr.StatusMaxQualifiedRequestsReached: 400,
+			evatr.StatusNotAuthorizedDE:             403,
+			evatr.StatusProcessingError3:            500,
+			evatr.StatusServiceUnavailable5:         503,
+		}
+		for status, code := range codes {
+			srv.SetResponse("ATU22222222", evatrtest.Response{Status: status})
+			_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU22222222")
+			require.Error(t, err)
+			assert.Equal(t, code, err.(*evatr.Error).StatusCode, status)
+		}
+	})
+
+	t.Run("qualified results", func(t *testing.T) {
+		srv.SetResponse("ATU33333333", evatrtest.Response{
+			Status:       evatr.StatusValid,
+			StreetResult: evatr.VerificationMismatch,
+		})
+
+		resp, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU33333333", "Test GmbH", "Wien", "Ring 1", "")
+		require.NoError(t, err)
+		assert.Equal(t, evatr.VerificationMatch, resp.CompanyNameResult)
+		assert.Equal(t, evatr.VerificationMismatch, resp.StreetResult)
+		assert.Equal(t, evatr.VerificationNotRequested, resp.PostalCodeResult)
+	})
+
+	t.Run("info endpoints", func(t *testing.T) {
+		messages, err := client.GetStatusMessages(t.Context())
+		require.NoError(t, err)
+		assert.Len(t, messages, 22)
+
+		srv.SetAvailable("FR", false)
+		states, err := client.GetEUMemberStates(t.Context())
+		require.NoError(t, err)
+		for _, state := range states {
+			assert.Equal(t, state.Alpha2 != "FR", state.Available, state.Alpha2)
+		}
+	})
+
+	t.Run("recorded requests", func(t *testing.T) {
+		srv.Reset()
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+
+		requests := srv.ValidationRequests()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "ATU12345678", requests[0].RequestedVATID)
+		assert.Contains(t, srv.Requests()[0].Header.Get("User-Agent"), "go-evatr")
+	})
+}
+
+// TestServerLatency tests latency injection
+func TestServerLatency(t *testing.T) {
+	srv := evatrtest.NewServer(evatrtest.WithLatency(50 * time.Millisecond))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := srv.Client().ValidateVAT(ctx, "DE123456789", "ATU12345678")
+	require.Error(t, err)
+
+	srv.SetLatency(0)
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusValid, Latency: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err = srv.Client().ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
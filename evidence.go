@@ -0,0 +1,91 @@
+package evatr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEvidence is returned when a validation was answered but the exchange
+// could not be recorded as evidence.
+var ErrEvidence = errors.New("evatr: recording evidence failed")
+
+// Exchange is a validation request and the answer of the API, byte for byte
+// as sent and received.
+type Exchange struct {
+	// Body sent to /v1/abfrage
+	Request []byte
+
+	// VAT IDs of the request
+	RequestingVATID string
+	RequestedVATID  string
+
+	// HTTP status code and body of the answer
+	StatusCode int
+	Response   []byte
+
+	// Technical ID and timestamp of a validation response, empty for error answers
+	ID               string
+	RequestTimestamp Time
+
+	// eVatR status of the response or error answer
+	Status StatusCode
+
+	// Attempt number, starting at 1
+	Attempt int
+
+	// Time the answer was received
+	ReceivedAt time.Time
+}
+
+// EvidenceRecorder keeps exchanges as proof of validations, e.g. for a tax
+// audit. See package evidence for a tamper-evident log.
+type EvidenceRecorder interface {
+	RecordExchange(ctx context.Context, ex Exchange) error
+}
+
+// WithEvidenceRecorder passes every answered validation request to the
+// recorder, including error answers and retried attempts. Cached results
+// are not recorded again.
+//
+// If recording fails, the validation fails with an error wrapping
+// ErrEvidence, so that no result is used without proof.
+func WithEvidenceRecorder(recorder EvidenceRecorder) Option {
+	return func(c *Client) {
+		c.evidence = recorder
+	}
+}
+
+// recordExchange passes an answered validation request to the recorder.
+func (c *Client) recordExchange(ctx context.Context, payload []byte, statusCode int, body []byte, resp *ValidationResponse, err error) error {
+	ex := Exchange{
+		Request:    payload,
+		StatusCode: statusCode,
+		Response:   body,
+		Attempt:    Attempt(ctx),
+		ReceivedAt: time.Now(),
+	}
+
+	var req ValidationRequest
+	if json.Unmarshal(payload, &req) == nil {
+		ex.RequestingVATID = req.RequestingVATID
+		ex.RequestedVATID = req.RequestedVATID
+	}
+
+	var apiErr *Error
+	switch {
+	case err == nil:
+		ex.ID = resp.ID
+		ex.RequestTimestamp = resp.RequestTimestamp
+		ex.Status = resp.Status
+	case errors.As(err, &apiErr):
+		ex.Status = apiErr.Status
+	}
+
+	if err := c.evidence.RecordExchange(ctx, ex); err != nil {
+		return fmt.Errorf("%w: %w", ErrEvidence, err)
+	}
+	return nil
+}
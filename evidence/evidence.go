@@ -0,0 +1,329 @@
+// Package evidence keeps validation exchanges in a hash-chained log, as
+// proof that a VAT ID was confirmed at invoice time.
+//
+// Every record holds the exact request sent to the API, the raw answer and
+// the hash of the previous record. Changing, inserting or removing a record
+// breaks the chain, which Verify detects:
+//
+//	log, err := evidence.Open("evidence.jsonl")
+//	...
+//	client := evatr.NewClient(evatr.WithEvidenceRecorder(log))
+//
+// The hashes are not keyed: whoever can write the file can also rewrite the
+// chain from any record on, or cut records off its end. The log is only
+// tamper-evident against a Head stored outside of it, e.g. in a daily
+// report or a write-once store, and compared to the result of Verify.
+package evidence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+)
+
+var (
+	// ErrBrokenChain is returned when a log was altered.
+	ErrBrokenChain = errors.New("evidence: broken chain")
+
+	// ErrTornTail is returned by Open when the log ends with an incomplete
+	// line, the remainder of an interrupted Append. Repair cuts it off.
+	ErrTornTail = errors.New("evidence: incomplete last record")
+)
+
+// maxLine limits the size of a record in the log.
+const maxLine = 1 << 20
+
+// Record is an entry of the log.
+type Record struct {
+	// Position in the log, starting at 1
+	Seq uint64 `json:"seq"`
+
+	// Hash of the previous record, empty for the first
+	PrevHash string `json:"prev_hash"`
+
+	// Time the record was appended
+	RecordedAt time.Time `json:"recorded_at"`
+
+	RequestingVATID string `json:"requesting_vat_id"`
+	RequestedVATID  string `json:"requested_vat_id"`
+
+	// Technical ID and timestamp of the validation response
+	ID               string     `json:"id,omitempty"`
+	RequestTimestamp evatr.Time `json:"request_timestamp,omitzero"`
+
+	Status     evatr.StatusCode `json:"status,omitempty"`
+	HTTPStatus int              `json:"http_status"`
+	Attempt    int              `json:"attempt"`
+	ReceivedAt time.Time        `json:"received_at"`
+
+	// Body sent to /v1/abfrage and the raw answer
+	Request  []byte `json:"request"`
+	Response []byte `json:"response"`
+}
+
+// Head identifies the last record of a log.
+type Head struct {
+	Seq  uint64
+	Hash string
+}
+
+// entry is a line of the log. The hash covers the raw record bytes, so
+// verification does not depend on re-encoding.
+type entry struct {
+	Hash   string          `json:"hash"`
+	Record json.RawMessage `json:"record"`
+}
+
+// Log is an append-only, hash-chained log in a file with one JSON entry per
+// line. It is safe for concurrent use.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	head Head
+
+	// size of the complete lines in f
+	size int64
+
+	// err is set when a failed Append could not be rolled back
+	err error
+}
+
+var _ evatr.EvidenceRecorder = (*Log)(nil)
+
+// Open opens or creates a log. An existing log is verified first. If it
+// ends with an incomplete line, Open fails with an error wrapping
+// ErrTornTail and leaves the file unchanged.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	size, total, err := completeSize(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("evidence: open log: %w", err)
+	}
+	if size < total {
+		f.Close()
+		return nil, fmt.Errorf("%w: %d bytes after the last complete record", ErrTornTail, total-size)
+	}
+
+	head, err := Verify(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Log{f: f, head: head, size: size}, nil
+}
+
+// Repair cuts off the incomplete last line of the log at path, the
+// remainder of an interrupted Append whose record was never confirmed to
+// the caller. It returns the number of bytes removed.
+func Repair(path string) (int64, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	size, total, err := completeSize(f)
+	if err == nil && size < total {
+		err = f.Truncate(size)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("evidence: repair log: %w", err)
+	}
+	return total - size, f.Sync()
+}
+
+// completeSize returns the size of f up to the end of its last complete
+// line and its total size.
+func completeSize(f *os.File) (size, total int64, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	total = info.Size()
+	if total == 0 {
+		return 0, 0, nil
+	}
+
+	// Append never writes lines of maxLine bytes or more, so the last
+	// newline is within the tail.
+	tail := make([]byte, min(total, maxLine))
+	if _, err := f.ReadAt(tail, total-int64(len(tail))); err != nil {
+		return 0, 0, err
+	}
+	i := bytes.LastIndexByte(tail, '\n')
+	if i < 0 && int64(len(tail)) < total {
+		return 0, 0, fmt.Errorf("%w: last line exceeds %d bytes", ErrBrokenChain, maxLine)
+	}
+	return total - int64(len(tail)) + int64(i) + 1, total, nil
+}
+
+// Head returns the last record of the log.
+func (l *Log) Head() Head {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+// Append adds an exchange to the log and syncs it to disk. If writing
+// fails, the log is truncated to its previous size.
+func (l *Log) Append(ex evatr.Exchange) (Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return Record{}, os.ErrClosed
+	}
+	if l.err != nil {
+		return Record{}, l.err
+	}
+
+	rec := Record{
+		Seq:              l.head.Seq + 1,
+		PrevHash:         l.head.Hash,
+		RecordedAt:       time.Now().UTC(),
+		RequestingVATID:  ex.RequestingVATID,
+		RequestedVATID:   ex.RequestedVATID,
+		ID:               ex.ID,
+		RequestTimestamp: ex.RequestTimestamp,
+		Status:           ex.Status,
+		HTTPStatus:       ex.StatusCode,
+		Attempt:          ex.Attempt,
+		ReceivedAt:       ex.ReceivedAt.UTC(),
+		Request:          ex.Request,
+		Response:         ex.Response,
+	}
+
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return Record{}, fmt.Errorf("evidence: encode record: %w", err)
+	}
+	e := entry{Hash: hash(raw), Record: raw}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Record{}, fmt.Errorf("evidence: encode record: %w", err)
+	}
+	line = append(line, '\n')
+	if len(line) >= maxLine {
+		return Record{}, fmt.Errorf("evidence: record of %d bytes exceeds %d bytes", len(line), maxLine)
+	}
+
+	if _, err := l.f.Write(line); err != nil {
+		return Record{}, l.rollback(fmt.Errorf("evidence: write record: %w", err))
+	}
+	if err := l.f.Sync(); err != nil {
+		return Record{}, l.rollback(fmt.Errorf("evidence: sync log: %w", err))
+	}
+
+	l.size += int64(len(line))
+	l.head = Head{Seq: rec.Seq, Hash: e.Hash}
+	return rec, nil
+}
+
+// rollback removes a partially written record after err. If that fails as
+// well, the log refuses further records, as they would break the chain.
+func (l *Log) rollback(err error) error {
+	if terr := l.f.Truncate(l.size); terr != nil {
+		l.err = fmt.Errorf("evidence: log unusable after failed write: %w", errors.Join(err, terr))
+		return l.err
+	}
+	return err
+}
+
+// RecordExchange implements evatr.EvidenceRecorder.
+func (l *Log) RecordExchange(_ context.Context, ex evatr.Exchange) error {
+	_, err := l.Append(ex)
+	return err
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// Scan verifies a log and calls fn for every record in order. It stops at
+// the first altered record with an error wrapping ErrBrokenChain, or when
+// fn returns an error.
+func Scan(r io.Reader, fn func(Record) error) (Head, error) {
+	var head Head
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return head, fmt.Errorf("%w: line %d: %v", ErrBrokenChain, line, err)
+		}
+		if hash(e.Record) != e.Hash {
+			return head, fmt.Errorf("%w: line %d: hash mismatch", ErrBrokenChain, line)
+		}
+
+		var rec Record
+		if err := json.Unmarshal(e.Record, &rec); err != nil {
+			return head, fmt.Errorf("%w: line %d: %v", ErrBrokenChain, line, err)
+		}
+		if rec.Seq != head.Seq+1 {
+			return head, fmt.Errorf("%w: line %d: sequence %d, want %d", ErrBrokenChain, line, rec.Seq, head.Seq+1)
+		}
+		if rec.PrevHash != head.Hash {
+			return head, fmt.Errorf("%w: line %d: previous hash mismatch", ErrBrokenChain, line)
+		}
+
+		if fn != nil {
+			if err := fn(rec); err != nil {
+				return head, err
+			}
+		}
+		head = Head{Seq: rec.Seq, Hash: e.Hash}
+	}
+
+	return head, scanner.Err()
+}
+
+// Verify verifies a log and returns its last record.
+func Verify(r io.Reader) (Head, error) {
+	return Scan(r, nil)
+}
+
+// VerifyFile verifies the log in a file and returns its last record.
+func VerifyFile(path string) (Head, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Head{}, err
+	}
+	defer f.Close()
+	return Verify(f)
+}
+
+func hash(record []byte) string {
+	sum := sha256.Sum256(record)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+package evidence_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/hostwithquantum/go-evatr/evidence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLog records three validations and returns the path of the log
+func writeLog(t *testing.T) string {
+	t.Helper()
+
+	srv := evatrtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+
+	path := filepath.Join(t.TempDir(), "evidence.jsonl")
+	log, err := evidence.Open(path)
+	require.NoError(t, err)
+	defer log.Close()
+
+	client := srv.Client(evatr.WithEvidenceRecorder(log))
+	for _, vatID := range []string{"ATU12345678", "ATU99999999", "FR12345678901"} {
+		_, _ = client.ValidateVAT(t.Context(), "DE123456789", vatID)
+	}
+	assert.Equal(t, uint64(3), log.Head().Seq)
+
+	return path
+}
+
+// TestLog tests records are chained and read back unchanged
+func TestLog(t *testing.T) {
+	path := writeLog(t)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []evidence.Record
+	head, err := evidence.Scan(f, func(rec evidence.Record) error {
+		records = append(records, rec)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, uint64(3), head.Seq)
+
+	first := records[0]
+	assert.Equal(t, uint64(1), first.Seq)
+	assert.Empty(t, first.PrevHash)
+	assert.Equal(t, "DE123456789", first.RequestingVATID)
+	assert.Equal(t, "ATU12345678", first.RequestedVATID)
+	assert.Equal(t, evatr.StatusValid, first.Status)
+	assert.Equal(t, 200, first.HTTPStatus)
+	assert.NotEmpty(t, first.ID)
+	assert.False(t, first.RequestTimestamp.IsZero())
+	assert.JSONEq(t, `{"anfragendeUstid":"DE123456789","angefragteUstid":"ATU12345678"}`, string(first.Request))
+	assert.Contains(t, string(first.Response), first.ID)
+
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, records[1].Status)
+	assert.Equal(t, 404, records[1].HTTPStatus)
+	assert.NotEmpty(t, records[1].PrevHash)
+
+	// Reopening continues the chain.
+	log, err := evidence.Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, head, log.Head())
+	rec, err := log.Append(evatr.Exchange{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), rec.Seq)
+	assert.Equal(t, head.Hash, rec.PrevHash)
+	require.NoError(t, log.Close())
+
+	_, err = log.Append(evatr.Exchange{})
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	head, err = evidence.VerifyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), head.Seq)
+}
+
+// TestVerifyTampered tests altered logs are detected
+func TestVerifyTampered(t *testing.T) {
+	path := writeLog(t)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := map[string]string{
+		"altered status":  strings.Replace(string(data), "evatr-2001", "evatr-0000", 1),
+		"removed record":  lines[0] + lines[2],
+		"reordered":       lines[1] + lines[0] + lines[2],
+		"duplicated":      lines[0] + lines[0] + lines[1],
+		"truncated entry": lines[0] + lines[1][:len(lines[1])/2],
+	}
+
+	for name, log := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := evidence.Verify(strings.NewReader(log))
+			assert.ErrorIs(t, err, evidence.ErrBrokenChain)
+		})
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(tests["altered status"]), 0o600))
+	_, err = evidence.Open(path)
+	assert.ErrorIs(t, err, evidence.ErrBrokenChain)
+
+	head, err := evidence.Verify(bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.Equal(t, evidence.Head{}, head)
+}
+
+// TestOpenTornTail tests an interrupted append is reported and repaired
+func TestOpenTornTail(t *testing.T) {
+	path := writeLog(t)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(data), "\n")
+	torn := lines[0] + lines[1] + lines[2][:len(lines[2])/2]
+	require.NoError(t, os.WriteFile(path, []byte(torn), 0o600))
+
+	_, err = evidence.Open(path)
+	require.ErrorIs(t, err, evidence.ErrTornTail)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, torn, string(data), "Open leaves the file unchanged")
+
+	n, err := evidence.Repair(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(lines[2])/2), n)
+
+	log, err := evidence.Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), log.Head().Seq)
+
+	rec, err := log.Append(evatr.Exchange{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), rec.Seq)
+	require.NoError(t, log.Close())
+
+	head, err := evidence.VerifyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), head.Seq)
+}
+
+// TestAppendTooLarge tests records Scan cannot read back are rejected
+func TestAppendTooLarge(t *testing.T) {
+	path := writeLog(t)
+	log, err := evidence.Open(path)
+	require.NoError(t, err)
+	defer log.Close()
+
+	_, err = log.Append(evatr.Exchange{Response: bytes.Repeat([]byte("x"), 1<<20)})
+	assert.Error(t, err)
+	assert.Equal(t, uint64(3), log.Head().Seq)
+
+	rec, err := log.Append(evatr.Exchange{RequestedVATID: "ATU12345678"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), rec.Seq)
+
+	head, err := evidence.VerifyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), head.Seq)
+}
@@ -0,0 +1,79 @@
+package evatr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exchangeRecorder struct {
+	mu        sync.Mutex
+	exchanges []evatr.Exchange
+	err       error
+}
+
+func (r *exchangeRecorder) RecordExchange(_ context.Context, ex evatr.Exchange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, ex)
+	return r.err
+}
+
+// TestEvidenceRecorder tests exchanges are recorded byte for byte
+func TestEvidenceRecorder(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+
+	recorder := &exchangeRecorder{}
+	client := srv.Client(evatr.WithEvidenceRecorder(recorder), evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy))
+
+	resp, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	require.NoError(t, err)
+	_, err = client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	require.NoError(t, err)
+	_, err = client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+	require.Error(t, err)
+
+	requests := srv.ValidationRequests()
+	require.Len(t, recorder.exchanges, 2, "cached result is not recorded again")
+
+	ex := recorder.exchanges[0]
+	assert.Equal(t, srv.Requests()[0].Body, ex.Request)
+	assert.Equal(t, requests[0].RequestingVATID, ex.RequestingVATID)
+	assert.Equal(t, "ATU12345678", ex.RequestedVATID)
+	assert.Equal(t, 200, ex.StatusCode)
+	assert.Contains(t, string(ex.Response), `"id":"`+resp.ID+`"`)
+	assert.Equal(t, resp.ID, ex.ID)
+	assert.Equal(t, resp.RequestTimestamp, ex.RequestTimestamp)
+	assert.Equal(t, evatr.StatusValid, ex.Status)
+	assert.Equal(t, 1, ex.Attempt)
+	assert.False(t, ex.ReceivedAt.IsZero())
+
+	ex = recorder.exchanges[1]
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, ex.Status)
+	assert.Equal(t, 404, ex.StatusCode)
+	assert.Contains(t, string(ex.Response), string(evatr.StatusVATIDNotAssigned))
+	assert.Empty(t, ex.ID)
+}
+
+// TestEvidenceRecorderError tests a result without evidence is not returned
+func TestEvidenceRecorderError(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	recorder := &exchangeRecorder{err: errors.New("disk full")}
+	client := srv.Client(evatr.WithEvidenceRecorder(recorder))
+
+	resp, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, evatr.ErrEvidence)
+	assert.ErrorContains(t, err, "disk full")
+	assert.False(t, evatr.IsRetryable(err))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -35,7 +36,8 @@ func simpleValidation(ctx context.Context, client *evatr.Client) {
 	// Use test values from the API documentation
 	result, err := client.ValidateVAT(ctx, "DE123456789", "ATU12345678")
 	if err != nil {
-		if evatrErr, ok := err.(*evatr.Error); ok {
+		var evatrErr *evatr.Error
+		if errors.As(err, &evatrErr) {
 			fmt.Printf("Validation error: %s (HTTP %d)\n", evatrErr.Message, evatrErr.StatusCode)
 			fmt.Printf("Status code: %s\n", evatrErr.Status)
 			return
@@ -49,10 +51,10 @@ func simpleValidation(ctx context.Context, client *evatr.Client) {
 		fmt.Printf("  Status: %s\n", result.Status)
 	} else {
 		fmt.Printf("✗ VAT ID is not valid: %s\n", result.Status)
-		if result.ValidFrom != "" {
+		if !result.ValidFrom.IsZero() {
 			fmt.Printf("  Valid from: %s\n", result.ValidFrom)
 		}
-		if result.ValidUntil != "" {
+		if !result.ValidUntil.IsZero() {
 			fmt.Printf("  Valid until: %s\n", result.ValidUntil)
 		}
 	}
@@ -71,7 +73,8 @@ func qualifiedValidation(ctx context.Context, client *evatr.Client) {
 	)
 
 	if err != nil {
-		if evatrErr, ok := err.(*evatr.Error); ok {
+		var evatrErr *evatr.Error
+		if errors.As(err, &evatrErr) {
 			fmt.Printf("Validation error: %s (HTTP %d)\n", evatrErr.Message, evatrErr.StatusCode)
 			return
 		}
@@ -4,18 +4,28 @@ import "context"
 
 // GetStatusMessages returns all status message descriptions.
 func (c *Client) GetStatusMessages(ctx context.Context) ([]StatusMessage, error) {
+	ctx, finish := c.startOperation(ctx, Operation{Name: OperationStatusMessages})
+
 	var result []StatusMessage
 	if err := c.doRequest(ctx, "GET", "/v1/info/statusmeldungen", nil, &result); err != nil {
+		finish(OperationResult{Err: err})
 		return nil, err
 	}
+
+	finish(OperationResult{})
 	return result, nil
 }
 
 // GetEUMemberStates returns EU member states and their VIES availability.
 func (c *Client) GetEUMemberStates(ctx context.Context) ([]EUMemberState, error) {
+	ctx, finish := c.startOperation(ctx, Operation{Name: OperationMemberStates})
+
 	var result []EUMemberState
 	if err := c.doRequest(ctx, "GET", "/v1/info/eu_mitgliedstaaten", nil, &result); err != nil {
+		finish(OperationResult{Err: err})
 		return nil, err
 	}
+
+	finish(OperationResult{MemberStates: result})
 	return result, nil
 }
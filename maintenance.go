@@ -0,0 +1,192 @@
+package evatr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMaintenanceWindow is returned when a request would fall into a
+// maintenance window and the client is configured to fail fast.
+var ErrMaintenanceWindow = errors.New("evatr: API is in its maintenance window")
+
+// MaintenanceMode controls how the Client behaves during a maintenance window.
+type MaintenanceMode int
+
+const (
+	// Send requests regardless of the schedule
+	MaintenanceIgnore MaintenanceMode = iota
+
+	// Return ErrMaintenanceWindow without sending the request
+	MaintenanceFailFast
+
+	// Block until the window ends (or the context is done)
+	MaintenanceWait
+)
+
+// DailyWindow is a maintenance window recurring every day. Start and End are
+// offsets from midnight in the schedule's time zone. If End is not after
+// Start, the window spans midnight.
+type DailyWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Window is a one-off maintenance window, e.g. an announced outage.
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+// MaintenanceSchedule describes when the eVatR API is not available.
+type MaintenanceSchedule struct {
+	// Recurring windows, evaluated in Location
+	Daily []DailyWindow
+
+	// Additional one-off windows
+	Extra []Window
+
+	// Time zone of the daily windows (defaults to Europe/Berlin)
+	Location *time.Location
+
+	// Clock used to determine the current time (defaults to time.Now)
+	Now func() time.Time
+}
+
+// DefaultMaintenanceSchedule returns the advertised daily maintenance window
+// of the eVatR API from 23:00 to 05:00 Europe/Berlin time.
+func DefaultMaintenanceSchedule() *MaintenanceSchedule {
+	return &MaintenanceSchedule{
+		Daily: []DailyWindow{
+			{Start: 23 * time.Hour, End: 5 * time.Hour},
+		},
+	}
+}
+
+// WithMaintenance makes the client consult the schedule before every attempt
+// of a validation request. Info requests such as GetStatusMessages are not
+// affected by maintenance.
+func WithMaintenance(schedule *MaintenanceSchedule, mode MaintenanceMode) Option {
+	return func(c *Client) {
+		c.maintenance = schedule
+		c.maintenanceMode = mode
+	}
+}
+
+// berlin is the time zone of the BZSt. Without tzdata on the host it falls
+// back to CET, in which case daily windows are off by one hour during summer.
+var berlin = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return time.FixedZone("CET", 60*60)
+	}
+	return loc
+}()
+
+func (s *MaintenanceSchedule) location() *time.Location {
+	if s.Location != nil {
+		return s.Location
+	}
+	return berlin
+}
+
+func (s *MaintenanceSchedule) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// window returns the window containing t.
+func (s *MaintenanceSchedule) window(t time.Time) (Window, bool) {
+	for _, w := range s.Extra {
+		if !t.Before(w.From) && t.Before(w.To) {
+			return w, true
+		}
+	}
+
+	local := t.In(s.location())
+	for _, d := range s.Daily {
+		length := d.End - d.Start
+		if length <= 0 {
+			length += 24 * time.Hour
+		}
+
+		// A window that spans midnight may have started the day before.
+		for _, offset := range []int{-1, 0} {
+			day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, s.location())
+			from := time.Date(day.Year(), day.Month(), day.Day(), 0, int(d.Start/time.Minute), 0, 0, s.location())
+			to := time.Date(day.Year(), day.Month(), day.Day(), 0, int((d.Start+length)/time.Minute), 0, 0, s.location())
+			if !t.Before(from) && t.Before(to) {
+				return Window{From: from, To: to}, true
+			}
+		}
+	}
+	return Window{}, false
+}
+
+// InMaintenance returns whether t falls into a maintenance window.
+func (s *MaintenanceSchedule) InMaintenance(t time.Time) bool {
+	_, ok := s.window(t)
+	return ok
+}
+
+// Active returns whether the API is in a maintenance window right now.
+func (s *MaintenanceSchedule) Active() bool {
+	return s.InMaintenance(s.now())
+}
+
+// NextAllowed returns the earliest time at or after t outside of all
+// maintenance windows. Adjacent or overlapping windows are skipped as one.
+func (s *MaintenanceSchedule) NextAllowed(t time.Time) time.Time {
+	// The bound protects against schedules that cover the whole day.
+	for range 100 {
+		w, ok := s.window(t)
+		if !ok {
+			return t
+		}
+		t = w.To
+	}
+	return t
+}
+
+// NextAllowedTime returns when the client may send the next request. Without a
+// maintenance schedule this is the current time.
+func (c *Client) NextAllowedTime() time.Time {
+	if c.maintenance == nil {
+		return time.Now()
+	}
+	return c.maintenance.NextAllowed(c.maintenance.now())
+}
+
+// awaitMaintenance applies the client's maintenance mode before an attempt.
+func (c *Client) awaitMaintenance(ctx context.Context) error {
+	if c.maintenance == nil || c.maintenanceMode == MaintenanceIgnore {
+		return nil
+	}
+
+	now := c.maintenance.now()
+	next := c.maintenance.NextAllowed(now)
+	if !next.After(now) {
+		return nil
+	}
+
+	if c.maintenanceMode == MaintenanceFailFast {
+		return fmt.Errorf("%w until %s", ErrMaintenanceWindow, next.Format(time.RFC3339))
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(next) {
+		return fmt.Errorf("%w until %s, after the context deadline", ErrMaintenanceWindow, next.Format(time.RFC3339))
+	}
+
+	timer := time.NewTimer(next.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package evatr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaintenanceSchedule tests the maintenance window model
+func TestMaintenanceSchedule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	schedule := evatr.DefaultMaintenanceSchedule()
+
+	testCases := []struct {
+		name     string
+		at       time.Time
+		inWindow bool
+		next     time.Time
+	}{
+		{"workday", time.Date(2025, 6, 2, 12, 0, 0, 0, berlin), false, time.Date(2025, 6, 2, 12, 0, 0, 0, berlin)},
+		{"just before", time.Date(2025, 6, 2, 22, 59, 0, 0, berlin), false, time.Date(2025, 6, 2, 22, 59, 0, 0, berlin)},
+		{"start", time.Date(2025, 6, 2, 23, 0, 0, 0, berlin), true, time.Date(2025, 6, 3, 5, 0, 0, 0, berlin)},
+		{"after midnight", time.Date(2025, 6, 3, 2, 30, 0, 0, berlin), true, time.Date(2025, 6, 3, 5, 0, 0, 0, berlin)},
+		{"end", time.Date(2025, 6, 3, 5, 0, 0, 0, berlin), false, time.Date(2025, 6, 3, 5, 0, 0, 0, berlin)},
+		{"UTC input", time.Date(2025, 1, 15, 22, 30, 0, 0, time.UTC), true, time.Date(2025, 1, 16, 5, 0, 0, 0, berlin)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.inWindow, schedule.InMaintenance(tc.at))
+			assert.True(t, tc.next.Equal(schedule.NextAllowed(tc.at)), "got %s", schedule.NextAllowed(tc.at))
+		})
+	}
+
+	t.Run("extra windows are chained", func(t *testing.T) {
+		schedule := evatr.DefaultMaintenanceSchedule()
+		schedule.Extra = []evatr.Window{{
+			From: time.Date(2025, 6, 3, 4, 0, 0, 0, berlin),
+			To:   time.Date(2025, 6, 3, 8, 0, 0, 0, berlin),
+		}}
+
+		next := schedule.NextAllowed(time.Date(2025, 6, 2, 23, 30, 0, 0, berlin))
+		assert.True(t, time.Date(2025, 6, 3, 8, 0, 0, 0, berlin).Equal(next))
+	})
+}
+
+// TestClientMaintenance tests the client's maintenance modes
+func TestClientMaintenance(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	schedule := &evatr.MaintenanceSchedule{
+		Extra: []evatr.Window{{From: now.Add(-time.Minute), To: now.Add(time.Hour)}},
+		Now:   func() time.Time { return now },
+	}
+
+	t.Run("fail fast", func(t *testing.T) {
+		client := evatr.NewClient(
+			evatr.WithBaseURL(server.URL),
+			evatr.WithMaintenance(schedule, evatr.MaintenanceFailFast),
+		)
+
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, evatr.ErrMaintenanceWindow))
+		assert.Equal(t, 0, calls)
+		assert.True(t, now.Add(time.Hour).Equal(client.NextAllowedTime()))
+	})
+
+	t.Run("wait gives up before the context deadline", func(t *testing.T) {
+		client := evatr.NewClient(
+			evatr.WithBaseURL(server.URL),
+			evatr.WithMaintenance(schedule, evatr.MaintenanceWait),
+		)
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		_, err := client.ValidateVAT(ctx, "DE123456789", "ATU12345678")
+		assert.True(t, errors.Is(err, evatr.ErrMaintenanceWindow))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("wait until the window ends", func(t *testing.T) {
+		schedule := &evatr.MaintenanceSchedule{
+			Extra: []evatr.Window{{From: time.Now().Add(-time.Minute), To: time.Now().Add(50 * time.Millisecond)}},
+		}
+		client := evatr.NewClient(
+			evatr.WithBaseURL(server.URL),
+			evatr.WithMaintenance(schedule, evatr.MaintenanceWait),
+		)
+
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		client := evatr.NewClient(
+			evatr.WithBaseURL(server.URL),
+			evatr.WithMaintenance(schedule, evatr.MaintenanceIgnore),
+		)
+
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+// TestClientMaintenanceRetry tests retries honour the schedule and info
+// requests ignore it
+func TestClientMaintenanceRetry(t *testing.T) {
+	start := time.Now()
+	var mu sync.Mutex
+	now := start
+	schedule := &evatr.MaintenanceSchedule{
+		Extra: []evatr.Window{{From: start.Add(time.Minute), To: start.Add(time.Hour)}},
+		Now: func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		},
+	}
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// The window starts while the first attempt is in flight.
+		mu.Lock()
+		now = start.Add(2 * time.Minute)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable1})
+	}))
+	defer server.Close()
+
+	client := evatr.NewClient(
+		evatr.WithBaseURL(server.URL),
+		evatr.WithMaintenance(schedule, evatr.MaintenanceFailFast),
+		evatr.WithRetryPolicy(evatr.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+
+	_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	assert.ErrorIs(t, err, evatr.ErrMaintenanceWindow)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Status messages are not affected by maintenance, even when waiting.
+	client = evatr.NewClient(
+		evatr.WithBaseURL(server.URL),
+		evatr.WithMaintenance(schedule, evatr.MaintenanceWait),
+	)
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	_, err = client.GetStatusMessages(ctx)
+	assert.NotErrorIs(t, err, evatr.ErrMaintenanceWindow)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,438 @@
+package evatr
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// Defaults of MonitorOptions.
+const (
+	DefaultMonitorInterval      = 24 * time.Hour
+	DefaultMonitorRetryInterval = time.Hour
+)
+
+// MonitorEntry is a VAT ID watched by a Monitor.
+type MonitorEntry struct {
+	// Identifies the entry, e.g. a customer number (defaults to the normalized
+	// requested VAT ID)
+	Key string
+
+	// VAT ID to validate, with company data for a qualified validation.
+	// RequestingVATID defaults to MonitorOptions.RequestingVATID.
+	Request ValidationRequest
+}
+
+// FieldChange is a changed verification result of a qualified validation.
+type FieldChange struct {
+	Field    QualifiedField
+	Old, New VerificationResult
+}
+
+// ChangeEvent reports a changed validation result of a monitored VAT ID.
+type ChangeEvent struct {
+	Entry MonitorEntry
+
+	// eVatR status before and after the change
+	OldStatus, NewStatus StatusCode
+
+	// Responses before and after the change, nil for error answers such as
+	// evatr-2001 and for the first check
+	Old, New *ValidationResponse
+
+	// Changed verification results of a qualified validation
+	Fields []FieldChange
+
+	CheckedAt time.Time
+
+	// The event reports the first result, see MonitorOptions.ReportInitial
+	Initial bool
+}
+
+// StatusChanged returns whether the eVatR status changed.
+func (e ChangeEvent) StatusChanged() bool {
+	return e.OldStatus != e.NewStatus
+}
+
+// Invalidated returns whether a valid VAT ID is no longer valid, e.g. after
+// a change from evatr-0000 to evatr-2001 or evatr-2006.
+func (e ChangeEvent) Invalidated() bool {
+	return e.OldStatus.IsValid() && !e.NewStatus.IsValid()
+}
+
+// MismatchedFields returns the fields that matched before and do not anymore.
+func (e ChangeEvent) MismatchedFields() []QualifiedField {
+	var fields []QualifiedField
+	for _, change := range e.Fields {
+		if change.Old == VerificationMatch && change.New != VerificationMatch {
+			fields = append(fields, change.Field)
+		}
+	}
+	return fields
+}
+
+// MonitorOptions configures a Monitor.
+type MonitorOptions struct {
+	// Requesting German VAT ID for entries without one
+	RequestingVATID string
+
+	// Time between validations of an entry (defaults to DefaultMonitorInterval)
+	Interval time.Duration
+
+	// Time until an entry is validated again after a failure (defaults to
+	// DefaultMonitorRetryInterval)
+	RetryInterval time.Duration
+
+	// Paces the validations of the monitor, e.g. NewRateLimiter(0.2, 1, 1),
+	// independent of the limiter of the client
+	Limiter Limiter
+
+	// Validations wait until a maintenance window ends (defaults to
+	// DefaultMaintenanceSchedule)
+	Maintenance *MaintenanceSchedule
+
+	// Provides the last known result of an entry before its first check
+	Store Store
+
+	// Called for changed results
+	OnChange func(ChangeEvent)
+
+	// Called when a validation failed without a result; the entry is
+	// validated again after RetryInterval
+	OnError func(MonitorEntry, error)
+
+	// Report the first result of an entry without a known previous result
+	ReportInitial bool
+
+	// Clock for scheduling (defaults to time.Now)
+	Now func() time.Time
+}
+
+// Monitor re-validates VAT IDs periodically and reports changed results,
+// e.g. deregistered customers.
+type Monitor struct {
+	client *Client
+	opts   MonitorOptions
+	wake   chan struct{}
+
+	mu      sync.Mutex
+	entries map[string]*monitorState
+}
+
+// monitorState is the last result and the schedule of an entry.
+type monitorState struct {
+	entry   MonitorEntry
+	known   bool
+	status  StatusCode
+	resp    *ValidationResponse
+	nextDue time.Time
+}
+
+// NewMonitor returns a monitor validating with the given client. The client
+// should not cache results for longer than the interval.
+func NewMonitor(client *Client, opts MonitorOptions) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultMonitorInterval
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultMonitorRetryInterval
+	}
+	if opts.Maintenance == nil {
+		opts.Maintenance = DefaultMaintenanceSchedule()
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Monitor{
+		client:  client,
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+		entries: make(map[string]*monitorState),
+	}
+}
+
+// Add adds entries, due right away. An entry with the key of an existing
+// entry replaces its request but keeps its last result, unless the VAT ID or
+// the company data changed; then the entry starts over with a new baseline.
+func (m *Monitor) Add(entries ...MonitorEntry) {
+	m.mu.Lock()
+	for _, entry := range entries {
+		if entry.Key == "" {
+			entry.Key = vatid.Normalize(entry.Request.RequestedVATID)
+		}
+		if st, ok := m.entries[entry.Key]; ok && sameRequest(st.entry.Request, entry.Request) {
+			st.entry = entry
+			continue
+		}
+		m.entries[entry.Key] = &monitorState{entry: entry}
+	}
+	m.mu.Unlock()
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Remove stops monitoring the entries with the given keys.
+func (m *Monitor) Remove(keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+}
+
+// Entries returns the monitored entries sorted by key.
+func (m *Monitor) Entries() []MonitorEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]MonitorEntry, 0, len(m.entries))
+	for _, st := range m.entries {
+		entries = append(entries, st.entry)
+	}
+	slices.SortFunc(entries, func(a, b MonitorEntry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return entries
+}
+
+// CheckDue validates all entries that are due, one at a time. It returns
+// early with the context's error if ctx is done.
+func (m *Monitor) CheckDue(ctx context.Context) error {
+	for _, st := range m.due() {
+		if err := m.await(ctx); err != nil {
+			return err
+		}
+		m.check(ctx, st)
+	}
+	return ctx.Err()
+}
+
+// Run checks due entries until ctx is done.
+func (m *Monitor) Run(ctx context.Context) {
+	for {
+		if m.CheckDue(ctx) != nil {
+			return
+		}
+
+		timer := time.NewTimer(m.untilNextDue())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-m.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// due returns the entries due now, oldest due first.
+func (m *Monitor) due() []*monitorState {
+	now := m.opts.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*monitorState
+	for _, st := range m.entries {
+		if !st.nextDue.After(now) {
+			due = append(due, st)
+		}
+	}
+	slices.SortFunc(due, func(a, b *monitorState) int {
+		if c := a.nextDue.Compare(b.nextDue); c != 0 {
+			return c
+		}
+		return strings.Compare(a.entry.Key, b.entry.Key)
+	})
+	return due
+}
+
+// untilNextDue returns the time until the next entry is due, at most the
+// interval.
+func (m *Monitor) untilNextDue() time.Duration {
+	now := m.opts.Now()
+	wait := m.opts.Interval
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, st := range m.entries {
+		wait = min(wait, st.nextDue.Sub(now))
+	}
+	return max(wait, 0)
+}
+
+// await waits for the end of a maintenance window and for the limiter.
+func (m *Monitor) await(ctx context.Context) error {
+	now := m.opts.Maintenance.now()
+	if next := m.opts.Maintenance.NextAllowed(now); next.After(now) {
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if m.opts.Limiter != nil {
+		release, err := m.opts.Limiter.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		// The limiter paces the checks; the client limits requests in flight.
+		release()
+	}
+	return nil
+}
+
+// check validates an entry and reports a changed result.
+func (m *Monitor) check(ctx context.Context, st *monitorState) {
+	m.mu.Lock()
+	entry := st.entry
+	known := st.known
+	m.mu.Unlock()
+
+	if !known {
+		m.loadBaseline(ctx, st, entry)
+	}
+
+	req := entry.Request
+	if req.RequestingVATID == "" {
+		req.RequestingVATID = m.opts.RequestingVATID
+	}
+	resp, err := m.client.ValidateVATWithRequest(ctx, &req)
+	now := m.opts.Now()
+
+	status, ok := observedStatus(resp, err)
+	if !ok {
+		m.mu.Lock()
+		st.nextDue = now.Add(m.opts.RetryInterval)
+		m.mu.Unlock()
+		if ctx.Err() == nil && m.opts.OnError != nil {
+			m.opts.OnError(entry, err)
+		}
+		return
+	}
+
+	m.mu.Lock()
+	event := ChangeEvent{
+		Entry:     entry,
+		OldStatus: st.status,
+		NewStatus: status,
+		Old:       st.resp,
+		New:       resp,
+		CheckedAt: now,
+		Initial:   !st.known,
+	}
+	st.known, st.status, st.resp = true, status, resp
+	st.nextDue = now.Add(m.opts.Interval)
+	m.mu.Unlock()
+
+	event.Fields = fieldChanges(event.Old, event.New)
+	changed := event.StatusChanged() || len(event.Fields) > 0
+	if event.Initial {
+		event.Fields = nil
+		changed = m.opts.ReportInitial
+	}
+	if changed && m.opts.OnChange != nil {
+		m.opts.OnChange(event)
+	}
+}
+
+// loadBaseline sets the last stored result of an entry as its previous result.
+func (m *Monitor) loadBaseline(ctx context.Context, st *monitorState, entry MonitorEntry) {
+	if m.opts.Store == nil {
+		return
+	}
+
+	q := RecordQuery{VATID: vatid.Normalize(entry.Request.RequestedVATID), Descending: true}
+	for {
+		page, err := m.opts.Store.Query(ctx, q)
+		if err != nil {
+			if m.opts.OnError != nil {
+				m.opts.OnError(entry, err)
+			}
+			return
+		}
+
+		for _, rec := range page.Records {
+			// Records checked with other company data are no baseline for
+			// the field results.
+			if !sameCompanyData(rec.Request, entry.Request) {
+				continue
+			}
+
+			var err error
+			if rec.Err != nil {
+				err = rec.Err
+			}
+			if status, ok := observedStatus(rec.Response, err); ok {
+				m.mu.Lock()
+				st.known, st.status, st.resp = true, status, rec.Response
+				m.mu.Unlock()
+				return
+			}
+		}
+
+		if page.NextCursor == "" {
+			return
+		}
+		q.Cursor = page.NextCursor
+	}
+}
+
+// sameRequest returns whether both requests ask for the same VAT ID and
+// company data.
+func sameRequest(a, b ValidationRequest) bool {
+	return vatid.Normalize(a.RequestedVATID) == vatid.Normalize(b.RequestedVATID) && sameCompanyData(a, b)
+}
+
+// sameCompanyData returns whether both requests ask for the same company
+// data.
+func sameCompanyData(a, b ValidationRequest) bool {
+	return a.CompanyName == b.CompanyName && a.City == b.City &&
+		a.Street == b.Street && a.PostalCode == b.PostalCode
+}
+
+// observedStatus returns the status of a result about the VAT ID, either a
+// response or an evatr-2001 answer.
+func observedStatus(resp *ValidationResponse, err error) (StatusCode, bool) {
+	if err == nil && resp != nil {
+		return resp.Status, !resp.Status.IsTransient()
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Category() == CategoryNotAssigned {
+		return apiErr.Status, true
+	}
+	return "", false
+}
+
+// fieldChanges compares the verification results of two responses. Fields
+// not compared in either response are left out.
+func fieldChanges(prev, next *ValidationResponse) []FieldChange {
+	var changes []FieldChange
+	for _, field := range QualifiedFields {
+		var o, n VerificationResult
+		if prev != nil {
+			o = prev.Result(field)
+		}
+		if next != nil {
+			n = next.Result(field)
+		}
+		if o == "" || n == "" || o == n {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+	}
+	return changes
+}
@@ -0,0 +1,310 @@
+package evatr_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock advanced by the test.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []evatr.ChangeEvent
+	errs   []error
+}
+
+func (r *eventRecorder) OnChange(e evatr.ChangeEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) OnError(_ evatr.MonitorEntry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *eventRecorder) take() []evatr.ChangeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// TestMonitor tests status and verification result changes are reported
+func TestMonitor(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2025, 9, 15, 10, 0, 0, 0, time.UTC)}
+	rec := &eventRecorder{}
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Interval:        24 * time.Hour,
+		RetryInterval:   time.Hour,
+		Maintenance:     &evatr.MaintenanceSchedule{},
+		OnChange:        rec.OnChange,
+		OnError:         rec.OnError,
+		Now:             clock.Now,
+	})
+	m.Add(
+		evatr.MonitorEntry{Key: "customer-1", Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}},
+		evatr.MonitorEntry{Key: "customer-2", Request: evatr.ValidationRequest{
+			RequestedVATID: "FR12345678901", CompanyName: "Exemple SARL", City: "Paris", Street: "Rue 1",
+		}},
+	)
+	assert.Len(t, m.Entries(), 2)
+
+	ctx := t.Context()
+	require.NoError(t, m.CheckDue(ctx))
+	assert.Empty(t, rec.take(), "first results are the baseline")
+	assert.Len(t, srv.ValidationRequests(), 2)
+
+	// Nothing is due before the interval has passed.
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+	clock.Advance(time.Hour)
+	require.NoError(t, m.CheckDue(ctx))
+	assert.Len(t, srv.ValidationRequests(), 2)
+
+	srv.SetResponse("FR12345678901", evatrtest.Response{Status: evatr.StatusValid, StreetResult: evatr.VerificationMismatch})
+	clock.Advance(23 * time.Hour)
+	require.NoError(t, m.CheckDue(ctx))
+
+	events := rec.take()
+	require.Len(t, events, 2)
+
+	deregistered := events[0]
+	assert.Equal(t, "customer-1", deregistered.Entry.Key)
+	assert.Equal(t, evatr.StatusValid, deregistered.OldStatus)
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, deregistered.NewStatus)
+	assert.NotNil(t, deregistered.Old)
+	assert.Nil(t, deregistered.New)
+	assert.True(t, deregistered.Invalidated())
+	assert.Equal(t, clock.Now(), deregistered.CheckedAt)
+
+	moved := events[1]
+	assert.Equal(t, "customer-2", moved.Entry.Key)
+	assert.False(t, moved.StatusChanged())
+	assert.False(t, moved.Invalidated())
+	assert.Equal(t, []evatr.FieldChange{{Field: evatr.FieldStreet, Old: evatr.VerificationMatch, New: evatr.VerificationMismatch}}, moved.Fields)
+	assert.Equal(t, []evatr.QualifiedField{evatr.FieldStreet}, moved.MismatchedFields())
+
+	// Unchanged results are not reported.
+	clock.Advance(24 * time.Hour)
+	require.NoError(t, m.CheckDue(ctx))
+	assert.Empty(t, rec.take())
+
+	// Failures are retried after the retry interval and keep the last result.
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusServiceUnavailable1})
+	m.Remove("customer-2")
+	clock.Advance(24 * time.Hour)
+	require.NoError(t, m.CheckDue(ctx))
+	assert.Empty(t, rec.take())
+	assert.Len(t, rec.errs, 1)
+
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusValid})
+	clock.Advance(time.Hour)
+	require.NoError(t, m.CheckDue(ctx))
+	events = rec.take()
+	require.Len(t, events, 1)
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, events[0].OldStatus)
+	assert.Equal(t, evatr.StatusValid, events[0].NewStatus)
+	assert.False(t, events[0].Invalidated())
+}
+
+// TestMonitorBaseline tests the last stored result and initial reports
+func TestMonitorBaseline(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusNoLongerValid, ValidUntil: "2025-06-30"})
+
+	store := evatr.NewMemoryStore()
+	_, err := store.Insert(t.Context(), evatr.ValidationRecord{
+		Request:  evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"},
+		Response: &evatr.ValidationResponse{Status: evatr.StatusValid},
+	})
+	require.NoError(t, err)
+
+	rec := &eventRecorder{}
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Maintenance:     &evatr.MaintenanceSchedule{},
+		Store:           store,
+		OnChange:        rec.OnChange,
+		ReportInitial:   true,
+	})
+	m.Add(
+		evatr.MonitorEntry{Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}},
+		evatr.MonitorEntry{Request: evatr.ValidationRequest{RequestedVATID: "FR12345678901"}},
+	)
+	require.NoError(t, m.CheckDue(t.Context()))
+
+	events := rec.take()
+	require.Len(t, events, 2)
+	assert.Equal(t, "ATU12345678", events[0].Entry.Key)
+	assert.False(t, events[0].Initial)
+	assert.True(t, events[0].Invalidated())
+	assert.Equal(t, "2025-06-30", events[0].New.ValidUntil.String())
+
+	assert.Equal(t, "FR12345678901", events[1].Entry.Key)
+	assert.True(t, events[1].Initial)
+	assert.Empty(t, events[1].OldStatus)
+	assert.Equal(t, evatr.StatusValid, events[1].NewStatus)
+}
+
+// TestMonitorBaselineCompanyData tests only records with the entry's company
+// data are a baseline
+func TestMonitorBaselineCompanyData(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	request := evatr.ValidationRequest{
+		RequestingVATID: "DE123456789", RequestedVATID: "FR12345678901",
+		CompanyName: "Exemple SARL", City: "Paris", Street: "Rue 1",
+	}
+	other := request
+	other.CompanyName = "Autre SARL"
+	simple := evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "FR12345678901"}
+
+	store := evatr.NewMemoryStore()
+	checkedAt := time.Now().Add(-72 * time.Hour)
+	for _, rec := range []evatr.ValidationRecord{
+		{Request: request, Response: &evatr.ValidationResponse{
+			Status: evatr.StatusValid, CompanyNameResult: evatr.VerificationMatch, CityResult: evatr.VerificationMatch, StreetResult: evatr.VerificationMatch,
+		}},
+		{Request: simple, Response: &evatr.ValidationResponse{Status: evatr.StatusValid}},
+		{Request: other, Response: &evatr.ValidationResponse{
+			Status: evatr.StatusValid, CompanyNameResult: evatr.VerificationMismatch, CityResult: evatr.VerificationMatch, StreetResult: evatr.VerificationMismatch,
+		}},
+	} {
+		checkedAt = checkedAt.Add(time.Hour)
+		rec.CheckedAt = checkedAt
+		_, err := store.Insert(t.Context(), rec)
+		require.NoError(t, err)
+	}
+
+	rec := &eventRecorder{}
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Maintenance:     &evatr.MaintenanceSchedule{},
+		Store:           store,
+		OnChange:        rec.OnChange,
+	})
+	unnormalized := request
+	unnormalized.RequestedVATID = "fr 12345678901"
+	m.Add(evatr.MonitorEntry{Request: unnormalized}, evatr.MonitorEntry{Request: request})
+	require.Len(t, m.Entries(), 1)
+	assert.Equal(t, "FR12345678901", m.Entries()[0].Key)
+
+	require.NoError(t, m.CheckDue(t.Context()))
+	assert.Empty(t, rec.take())
+}
+
+// TestMonitorReplace tests new company data for a key start a new baseline
+func TestMonitorReplace(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	rec := &eventRecorder{}
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Maintenance:     &evatr.MaintenanceSchedule{},
+		OnChange:        rec.OnChange,
+		ReportInitial:   true,
+	})
+	request := evatr.ValidationRequest{RequestedVATID: "FR12345678901", CompanyName: "Exemple SARL", City: "Paris"}
+	m.Add(evatr.MonitorEntry{Key: "customer-1", Request: request})
+	require.NoError(t, m.CheckDue(t.Context()))
+	require.Len(t, rec.take(), 1)
+
+	// The same request keeps the last result and schedule.
+	m.Add(evatr.MonitorEntry{Key: "customer-1", Request: request})
+	require.NoError(t, m.CheckDue(t.Context()))
+	assert.Empty(t, rec.take())
+
+	srv.SetResponse("FR12345678901", evatrtest.Response{Status: evatr.StatusValid, CompanyNameResult: evatr.VerificationMismatch})
+	request.CompanyName = "Autre SARL"
+	m.Add(evatr.MonitorEntry{Key: "customer-1", Request: request})
+	require.NoError(t, m.CheckDue(t.Context()))
+
+	events := rec.take()
+	require.Len(t, events, 1)
+	assert.True(t, events[0].Initial)
+	assert.Nil(t, events[0].Old)
+	assert.Empty(t, events[0].Fields)
+	assert.Equal(t, "Autre SARL", events[0].Entry.Request.CompanyName)
+}
+
+// TestMonitorMaintenance tests checks wait for the end of a maintenance window
+func TestMonitorMaintenance(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	now := time.Now()
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Maintenance: &evatr.MaintenanceSchedule{
+			Extra: []evatr.Window{{From: now.Add(-time.Hour), To: now.Add(50 * time.Millisecond)}},
+		},
+	})
+	m.Add(evatr.MonitorEntry{Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.CheckDue(ctx), context.DeadlineExceeded)
+	assert.Empty(t, srv.ValidationRequests())
+
+	require.NoError(t, m.CheckDue(t.Context()))
+	assert.Len(t, srv.ValidationRequests(), 1)
+	assert.False(t, time.Now().Before(now.Add(50*time.Millisecond)))
+}
+
+// TestMonitorRun tests entries added while running are checked
+func TestMonitorRun(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	m := evatr.NewMonitor(srv.Client(), evatr.MonitorOptions{
+		RequestingVATID: "DE123456789",
+		Maintenance:     &evatr.MaintenanceSchedule{},
+		Limiter:         evatr.NewRateLimiter(1000, 1, 1),
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+
+	m.Add(evatr.MonitorEntry{Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}})
+	assert.Eventually(t, func() bool { return len(srv.ValidationRequests()) == 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+)
+
+// Defaults of DispatcherOptions.
+const (
+	DefaultBatchSize  = 50
+	DefaultBatchDelay = time.Minute
+	DefaultQueueSize  = 1000
+)
+
+// ErrStopped is passed to OnError with events handled after Run returned.
+var ErrStopped = errors.New("notify: dispatcher stopped")
+
+// DefaultRetryPolicy retries deliveries for about half an hour.
+var DefaultRetryPolicy = evatr.RetryPolicy{
+	MaxAttempts:    8,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     10 * time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// DispatcherOptions configures a Dispatcher.
+type DispatcherOptions struct {
+	// Maximum number of events per delivery (defaults to DefaultBatchSize)
+	BatchSize int
+
+	// Time to wait for more events after the first event of a batch
+	// (defaults to DefaultBatchDelay)
+	BatchDelay time.Duration
+
+	// Events queued before Handle blocks while Run is busy (defaults to
+	// DefaultQueueSize)
+	QueueSize int
+
+	// Retries of failed deliveries (defaults to DefaultRetryPolicy); errors
+	// wrapping ErrPermanent are not retried. Set MaxAttempts to 1 to disable.
+	Retry evatr.RetryPolicy
+
+	// Called with a batch that could not be delivered, from Run or, once Run
+	// returned, from Handle
+	OnError func([]evatr.ChangeEvent, error)
+}
+
+// Dispatcher batches change events and delivers them to a Notifier.
+type Dispatcher struct {
+	notifier Notifier
+	opts     DispatcherOptions
+	queue    chan evatr.ChangeEvent
+
+	// done is closed when Run returns
+	done chan struct{}
+}
+
+// NewDispatcher returns a dispatcher delivering to n. Deliveries start with
+// Run.
+func NewDispatcher(n Notifier, opts DispatcherOptions) *Dispatcher {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.BatchDelay <= 0 {
+		opts.BatchDelay = DefaultBatchDelay
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Retry.MaxAttempts <= 0 {
+		opts.Retry = DefaultRetryPolicy
+	}
+	return &Dispatcher{
+		notifier: n,
+		opts:     opts,
+		queue:    make(chan evatr.ChangeEvent, opts.QueueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// Handle queues an event. It can be used as evatr.MonitorOptions.OnChange.
+// Once Run returned, events are passed to OnError with ErrStopped instead.
+func (d *Dispatcher) Handle(e evatr.ChangeEvent) {
+	select {
+	case <-d.done:
+		d.fail([]evatr.ChangeEvent{e}, ErrStopped)
+		return
+	default:
+	}
+
+	select {
+	case d.queue <- e:
+	case <-d.done:
+		d.fail([]evatr.ChangeEvent{e}, ErrStopped)
+		return
+	}
+
+	// Run may have drained the queue before e was added.
+	select {
+	case <-d.done:
+		d.fail(d.drain(nil), ErrStopped)
+	default:
+	}
+}
+
+// Run delivers queued events until ctx is done. Events not delivered by
+// then are passed to OnError. Run must be called only once.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var (
+		batch []evatr.ChangeEvent
+		timer *time.Timer
+		due   <-chan time.Time
+	)
+
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, due = nil, nil
+		}
+		d.deliver(ctx, batch)
+		batch = nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			close(d.done)
+			d.fail(d.drain(batch), ctx.Err())
+			return
+
+		case e := <-d.queue:
+			batch = append(batch, e)
+			if len(batch) >= d.opts.BatchSize {
+				flush()
+			} else if timer == nil {
+				timer = time.NewTimer(d.opts.BatchDelay)
+				due = timer.C
+			}
+
+		case <-due:
+			timer, due = nil, nil
+			flush()
+		}
+	}
+}
+
+// deliver sends a batch, retrying failures.
+func (d *Dispatcher) deliver(ctx context.Context, batch []evatr.ChangeEvent) {
+	for attempt := 1; ; attempt++ {
+		err := d.notifier.Notify(ctx, batch)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ErrPermanent) || attempt >= d.opts.Retry.MaxAttempts || ctx.Err() != nil {
+			d.fail(batch, err)
+			return
+		}
+
+		timer := time.NewTimer(backoff(d.opts.Retry, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.fail(batch, err)
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the randomized exponential wait of p after the given
+// attempt, starting at 1, the way the client waits between its attempts.
+func backoff(p evatr.RetryPolicy, attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		d = min(d, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		d += d * min(p.Jitter, 1) * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+// drain appends the queued events to batch.
+func (d *Dispatcher) drain(batch []evatr.ChangeEvent) []evatr.ChangeEvent {
+	for {
+		select {
+		case e := <-d.queue:
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+func (d *Dispatcher) fail(batch []evatr.ChangeEvent, err error) {
+	if len(batch) > 0 && d.opts.OnError != nil {
+		d.opts.OnError(batch, err)
+	}
+}
@@ -0,0 +1,171 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder records deliveries and fails the first failures of them.
+type batchRecorder struct {
+	mu       sync.Mutex
+	batches  [][]evatr.ChangeEvent
+	failures int
+	err      error
+	failed   [][]evatr.ChangeEvent
+}
+
+func (r *batchRecorder) Notify(_ context.Context, events []evatr.ChangeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return r.err
+	}
+	r.batches = append(r.batches, events)
+	return nil
+}
+
+func (r *batchRecorder) OnError(events []evatr.ChangeEvent, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, events)
+}
+
+func (r *batchRecorder) sizes() (delivered, failed []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.batches {
+		delivered = append(delivered, len(b))
+	}
+	for _, b := range r.failed {
+		failed = append(failed, len(b))
+	}
+	return delivered, failed
+}
+
+func event(i int) evatr.ChangeEvent {
+	return evatr.ChangeEvent{Entry: evatr.MonitorEntry{Key: fmt.Sprint(i)}, NewStatus: evatr.StatusValid}
+}
+
+var fastRetry = evatr.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+func runDispatcher(t *testing.T, d *notify.Dispatcher) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// TestDispatcherBatching tests batches are flushed by size and delay
+func TestDispatcherBatching(t *testing.T) {
+	rec := &batchRecorder{}
+	d := notify.NewDispatcher(rec, notify.DispatcherOptions{BatchSize: 3, BatchDelay: 50 * time.Millisecond, Retry: fastRetry})
+	stop := runDispatcher(t, d)
+	defer stop()
+
+	for i := range 4 {
+		d.Handle(event(i))
+	}
+	assert.Eventually(t, func() bool {
+		delivered, _ := rec.sizes()
+		return len(delivered) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	delivered, failed := rec.sizes()
+	assert.Equal(t, []int{3, 1}, delivered)
+	assert.Empty(t, failed)
+	assert.Equal(t, "3", rec.batches[1][0].Entry.Key)
+}
+
+// TestDispatcherRetry tests failed deliveries are retried unless permanent
+func TestDispatcherRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		delivered []int
+		failed    []int
+	}{
+		{"transient", 2, errors.New("unavailable"), []int{1}, nil},
+		{"exhausted", 3, errors.New("unavailable"), nil, []int{1}},
+		{"permanent", 1, fmt.Errorf("%w: rejected", notify.ErrPermanent), nil, []int{1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &batchRecorder{failures: tc.failures, err: tc.err}
+			d := notify.NewDispatcher(rec, notify.DispatcherOptions{
+				BatchDelay: time.Millisecond,
+				Retry:      fastRetry,
+				OnError:    rec.OnError,
+			})
+			stop := runDispatcher(t, d)
+			defer stop()
+
+			d.Handle(event(0))
+			assert.Eventually(t, func() bool {
+				delivered, failed := rec.sizes()
+				return len(delivered)+len(failed) == 1
+			}, time.Second, time.Millisecond)
+
+			delivered, failed := rec.sizes()
+			assert.Equal(t, tc.delivered, delivered)
+			assert.Equal(t, tc.failed, failed)
+		})
+	}
+}
+
+// TestDispatcherStop tests pending events are reported when stopped
+func TestDispatcherStop(t *testing.T) {
+	rec := &batchRecorder{}
+	d := notify.NewDispatcher(rec, notify.DispatcherOptions{BatchDelay: time.Hour, OnError: rec.OnError})
+	stop := runDispatcher(t, d)
+
+	d.Handle(event(0))
+	d.Handle(event(1))
+	stop()
+
+	delivered, failed := rec.sizes()
+	assert.Empty(t, delivered)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 2, failed[0])
+}
+
+// TestDispatcherHandleStopped tests events after Run returned do not block
+func TestDispatcherHandleStopped(t *testing.T) {
+	rec := &batchRecorder{}
+	var errs []error
+	d := notify.NewDispatcher(rec, notify.DispatcherOptions{QueueSize: 1, OnError: func(events []evatr.ChangeEvent, err error) {
+		rec.OnError(events, err)
+		errs = append(errs, err)
+	}})
+	stop := runDispatcher(t, d)
+	stop()
+
+	for i := range 3 {
+		d.Handle(event(i))
+	}
+
+	delivered, failed := rec.sizes()
+	assert.Empty(t, delivered)
+	assert.Equal(t, []int{1, 1, 1}, failed)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, notify.ErrStopped)
+	}
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+)
+
+// Email sends change events as plain text email via SMTP. The server is
+// used with STARTTLS if it offers it.
+//
+// The SMTP session is bound to the context of Notify and limited to one
+// minute if the context has no deadline.
+type Email struct {
+	// Address of the SMTP server, host:port
+	Addr string
+
+	// Authentication, e.g. smtp.PlainAuth (optional)
+	Auth smtp.Auth
+
+	From string
+	To   []string
+
+	// Subject and body of the message
+	Template Template
+}
+
+const emailTimeout = time.Minute
+
+// Notify implements Notifier. Answers with an SMTP 5xx code wrap
+// ErrPermanent.
+func (e *Email) Notify(ctx context.Context, events []evatr.ChangeEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(e.To) == 0 {
+		return fmt.Errorf("%w: email: no recipients", ErrPermanent)
+	}
+
+	subject, body, err := e.Template.Render(events)
+	if err != nil {
+		return fmt.Errorf("%w: email: render template: %w", ErrPermanent, err)
+	}
+
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return fmt.Errorf("%w: email: from: %w", ErrPermanent, err)
+	}
+	to := make([]string, len(e.To))
+	for i, addr := range e.To {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("%w: email: to: %w", ErrPermanent, err)
+		}
+		to[i] = parsed.Address
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+
+	if err := e.send(ctx, from.Address, to, msg.Bytes()); err != nil {
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+			return fmt.Errorf("%w: email: %w", ErrPermanent, err)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("notify: email: %w", ctx.Err())
+		}
+		return fmt.Errorf("notify: email: %w", err)
+	}
+	return nil
+}
+
+// send is smtp.SendMail with a context.
+func (e *Email) send(ctx context.Context, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if e.Auth != nil {
+		if err := c.Auth(e.Auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
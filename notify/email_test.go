@@ -0,0 +1,148 @@
+package notify_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpMessage is a message received by smtpServer.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpServer accepts a single SMTP session without STARTTLS and rejects
+// recipients in the reject set with 550.
+func smtpServer(t *testing.T, reject ...string) (addr string, received <-chan smtpMessage) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	ch := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		reply := func(line string) { _ = tp.PrintfLine("%s", line) }
+		reply("220 localhost ESMTP")
+
+		var msg smtpMessage
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				rcpt := strings.Trim(line[len("RCPT TO:"):], "<> ")
+				if slices.Contains(reject, rcpt) {
+					reply("550 no such user")
+					continue
+				}
+				msg.to = append(msg.to, rcpt)
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				reply("250 OK")
+				ch <- msg
+			case cmd == "RSET", cmd == "NOOP":
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	return l.Addr().String(), ch
+}
+
+// TestEmail tests the message sent via SMTP
+func TestEmail(t *testing.T) {
+	addr, received := smtpServer(t)
+
+	email := &notify.Email{
+		Addr: addr,
+		From: "eVatR Monitor <monitor@example.com>",
+		To:   []string{"billing@example.com", "Tax <tax@example.com>"},
+	}
+	require.NoError(t, email.Notify(t.Context(), testEvents()))
+
+	msg := <-received
+	assert.Equal(t, "monitor@example.com", msg.from)
+	assert.Equal(t, []string{"billing@example.com", "tax@example.com"}, msg.to)
+
+	header, body, ok := strings.Cut(msg.data, "\n\n")
+	require.True(t, ok)
+	assert.Contains(t, header, "Subject: eVatR: 2 VAT ID changes, 1 no longer valid\n")
+	assert.Contains(t, header, "To: billing@example.com, Tax <tax@example.com>\n")
+	assert.Contains(t, header, "Content-Type: text/plain; charset=utf-8\n")
+	assert.Contains(t, body, "customer-1 (ATU12345678): evatr-0000 -> evatr-2001 (no longer valid)\n")
+	assert.Contains(t, body, "  street: A -> B\n")
+}
+
+// TestEmailRejected tests rejected recipients are permanent failures
+func TestEmailRejected(t *testing.T) {
+	addr, _ := smtpServer(t, "unknown@example.com")
+
+	email := &notify.Email{Addr: addr, From: "monitor@example.com", To: []string{"unknown@example.com"}}
+	assert.ErrorIs(t, email.Notify(t.Context(), testEvents()), notify.ErrPermanent)
+
+	email.To = nil
+	assert.ErrorIs(t, email.Notify(t.Context(), testEvents()), notify.ErrPermanent)
+}
+
+// TestEmailContext tests a hanging server does not outlive the context
+func TestEmailContext(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// never greet
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	email := &notify.Email{Addr: l.Addr().String(), From: "monitor@example.com", To: []string{"billing@example.com"}}
+	start := time.Now()
+	err = email.Notify(ctx, testEvents())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, notify.ErrPermanent)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,180 @@
+// Package notify delivers change events of an evatr.Monitor, e.g. to the
+// finance team when a customer's VAT ID is deregistered.
+//
+// A Dispatcher batches the events and retries failed deliveries:
+//
+//	webhook := &notify.Webhook{URL: "https://erp.example.com/hooks/evatr", Secret: secret}
+//	dispatcher := notify.NewDispatcher(webhook, notify.DispatcherOptions{})
+//	go dispatcher.Run(ctx)
+//
+//	monitor := evatr.NewMonitor(client, evatr.MonitorOptions{
+//		OnChange: dispatcher.Handle,
+//	})
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"text/template"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+)
+
+// ErrPermanent is wrapped by delivery errors that a retry cannot fix, e.g.
+// a rejected webhook request.
+var ErrPermanent = errors.New("notify: permanent failure")
+
+// Notifier delivers a batch of change events.
+type Notifier interface {
+	Notify(ctx context.Context, events []evatr.ChangeEvent) error
+}
+
+// Func is a Notifier calling a function.
+type Func func(ctx context.Context, events []evatr.ChangeEvent) error
+
+// Notify implements Notifier.
+func (f Func) Notify(ctx context.Context, events []evatr.ChangeEvent) error {
+	return f(ctx, events)
+}
+
+// Channel returns a Notifier sending every event to ch. It blocks until the
+// event is received or ctx is done.
+func Channel(ch chan<- evatr.ChangeEvent) Notifier {
+	return Func(func(ctx context.Context, events []evatr.ChangeEvent) error {
+		for _, e := range events {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// FieldChange is a changed verification result of a Change.
+type FieldChange struct {
+	Field evatr.QualifiedField     `json:"field"`
+	Old   evatr.VerificationResult `json:"old"`
+	New   evatr.VerificationResult `json:"new"`
+}
+
+// Change is a change event as sent in webhooks and rendered in templates.
+type Change struct {
+	// Key and VAT ID of the monitored entry
+	Key   string `json:"key"`
+	VATID string `json:"vat_id"`
+
+	// eVatR status before and after the change, with their meaning
+	OldStatus  evatr.StatusCode `json:"old_status,omitempty"`
+	NewStatus  evatr.StatusCode `json:"new_status"`
+	OldMessage string           `json:"old_message,omitempty"`
+	NewMessage string           `json:"new_message,omitempty"`
+
+	// Changed verification results of a qualified validation
+	Fields []FieldChange `json:"fields,omitempty"`
+
+	// The VAT ID was valid before and is not anymore
+	Invalidated bool `json:"invalidated"`
+
+	// The change reports the first result of the entry
+	Initial bool `json:"initial,omitempty"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// NewChange converts an event, with status messages in the given language.
+func NewChange(e evatr.ChangeEvent, lang evatr.Language) Change {
+	c := Change{
+		Key:         e.Entry.Key,
+		VATID:       e.Entry.Request.RequestedVATID,
+		OldStatus:   e.OldStatus,
+		NewStatus:   e.NewStatus,
+		Invalidated: e.Invalidated(),
+		Initial:     e.Initial,
+		CheckedAt:   e.CheckedAt,
+	}
+	c.OldMessage, _ = e.OldStatus.Translate(lang)
+	c.NewMessage, _ = e.NewStatus.Translate(lang)
+	for _, f := range e.Fields {
+		c.Fields = append(c.Fields, FieldChange{Field: f.Field, Old: f.Old, New: f.New})
+	}
+	return c
+}
+
+// NewChanges converts a batch of events.
+func NewChanges(events []evatr.ChangeEvent, lang evatr.Language) []Change {
+	changes := make([]Change, len(events))
+	for i, e := range events {
+		changes[i] = NewChange(e, lang)
+	}
+	return changes
+}
+
+// Default templates of a Template. They are executed with TemplateData.
+var (
+	DefaultSubject = template.Must(template.New("subject").Parse(
+		`eVatR: {{len .Changes}} VAT ID change{{if ne (len .Changes) 1}}s{{end}}` +
+			`{{if .Invalidated}}, {{.Invalidated}} no longer valid{{end}}`))
+
+	DefaultBody = template.Must(template.New("body").Parse(
+		`{{range .Changes}}{{.Key}}{{if ne .Key .VATID}} ({{.VATID}}){{end}}: ` +
+			`{{with .OldStatus}}{{.}}{{else}}new{{end}} -> {{.NewStatus}}{{if .Invalidated}} (no longer valid){{end}}
+{{with .NewMessage}}  {{.}}
+{{end}}{{range .Fields}}  {{.Field}}: {{.Old}} -> {{.New}}
+{{end}}{{end}}`))
+)
+
+// TemplateData is passed to the templates.
+type TemplateData struct {
+	Changes []Change
+
+	// Number of changes with Invalidated set
+	Invalidated int
+}
+
+// Template renders the subject and body of a message.
+type Template struct {
+	// Defaults to DefaultSubject and DefaultBody
+	Subject *template.Template
+	Body    *template.Template
+
+	// Language of status messages (defaults to English)
+	Language evatr.Language
+}
+
+// Render renders a batch of events.
+func (t Template) Render(events []evatr.ChangeEvent) (subject, body string, err error) {
+	lang := t.Language
+	if lang == "" {
+		lang = evatr.LanguageEnglish
+	}
+	subjectTmpl, bodyTmpl := t.Subject, t.Body
+	if subjectTmpl == nil {
+		subjectTmpl = DefaultSubject
+	}
+	if bodyTmpl == nil {
+		bodyTmpl = DefaultBody
+	}
+
+	data := TemplateData{Changes: NewChanges(events, lang)}
+	for _, c := range data.Changes {
+		if c.Invalidated {
+			data.Invalidated++
+		}
+	}
+
+	var b bytes.Buffer
+	if err := subjectTmpl.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	subject = b.String()
+
+	b.Reset()
+	if err := bodyTmpl.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	return subject, b.String(), nil
+}
@@ -0,0 +1,93 @@
+package notify_test
+
+import (
+	"context"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var checkedAt = time.Date(2025, 9, 15, 10, 0, 0, 0, time.UTC)
+
+// testEvents returns a deregistration and a changed street.
+func testEvents() []evatr.ChangeEvent {
+	return []evatr.ChangeEvent{
+		{
+			Entry:     evatr.MonitorEntry{Key: "customer-1", Request: evatr.ValidationRequest{RequestedVATID: "ATU12345678"}},
+			OldStatus: evatr.StatusValid,
+			NewStatus: evatr.StatusVATIDNotAssigned,
+			CheckedAt: checkedAt,
+		},
+		{
+			Entry:     evatr.MonitorEntry{Key: "FR12345678901", Request: evatr.ValidationRequest{RequestedVATID: "FR12345678901"}},
+			OldStatus: evatr.StatusValid,
+			NewStatus: evatr.StatusValid,
+			Fields:    []evatr.FieldChange{{Field: evatr.FieldStreet, Old: evatr.VerificationMatch, New: evatr.VerificationMismatch}},
+			CheckedAt: checkedAt,
+		},
+	}
+}
+
+// TestNewChange tests the conversion of events
+func TestNewChange(t *testing.T) {
+	c := notify.NewChange(testEvents()[0], evatr.LanguageGerman)
+	assert.Equal(t, "customer-1", c.Key)
+	assert.Equal(t, "ATU12345678", c.VATID)
+	assert.Equal(t, evatr.StatusValid, c.OldStatus)
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, c.NewStatus)
+	assert.Equal(t, "Die angefragte Ust-IdNr. ist zum Anfragezeitpunkt gültig.", c.OldMessage)
+	assert.True(t, c.Invalidated)
+	assert.Empty(t, c.Fields)
+
+	c = notify.NewChange(testEvents()[1], evatr.LanguageEnglish)
+	assert.False(t, c.Invalidated)
+	assert.Equal(t, []notify.FieldChange{{Field: evatr.FieldStreet, Old: "A", New: "B"}}, c.Fields)
+}
+
+// TestTemplateRender tests the default and custom templates
+func TestTemplateRender(t *testing.T) {
+	subject, body, err := notify.Template{}.Render(testEvents())
+	require.NoError(t, err)
+	assert.Equal(t, "eVatR: 2 VAT ID changes, 1 no longer valid", subject)
+	assert.Equal(t, `customer-1 (ATU12345678): evatr-0000 -> evatr-2001 (no longer valid)
+  The requested VAT ID is not assigned at the time of the request.
+FR12345678901: evatr-0000 -> evatr-0000
+  The requested VAT ID is valid at the time of the request.
+  street: A -> B
+`, body)
+
+	subject, _, err = notify.Template{}.Render(testEvents()[1:])
+	require.NoError(t, err)
+	assert.Equal(t, "eVatR: 1 VAT ID change", subject)
+
+	custom := notify.Template{
+		Subject:  template.Must(template.New("").Parse(`{{range .Changes}}{{.VATID}} {{end}}`)),
+		Body:     template.Must(template.New("").Parse(`{{range .Changes}}{{.NewMessage}}{{end}}`)),
+		Language: evatr.LanguageGerman,
+	}
+	subject, body, err = custom.Render(testEvents()[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "ATU12345678 ", subject)
+	assert.Equal(t, "Die angefragte USt-IdNr. ist zum Anfragezeitpunkt nicht vergeben.", body)
+
+	broken := notify.Template{Body: template.Must(template.New("").Parse(`{{.Missing}}`))}
+	_, _, err = broken.Render(testEvents())
+	assert.Error(t, err)
+}
+
+// TestChannel tests events are sent one by one
+func TestChannel(t *testing.T) {
+	ch := make(chan evatr.ChangeEvent, 2)
+	require.NoError(t, notify.Channel(ch).Notify(t.Context(), testEvents()))
+	assert.Equal(t, "customer-1", (<-ch).Entry.Key)
+	assert.Equal(t, "FR12345678901", (<-ch).Entry.Key)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	assert.ErrorIs(t, notify.Channel(make(chan evatr.ChangeEvent)).Notify(ctx, testEvents()), context.Canceled)
+}
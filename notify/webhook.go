@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+)
+
+// Headers of webhook requests.
+const (
+	HeaderTimestamp = "X-Evatr-Timestamp"
+	HeaderSignature = "X-Evatr-Signature"
+)
+
+// ErrInvalidSignature is returned by VerifySignature.
+var ErrInvalidSignature = errors.New("notify: invalid signature")
+
+// WebhookPayload is the JSON body of a webhook request.
+type WebhookPayload struct {
+	Changes []Change  `json:"changes"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Webhook posts change events as WebhookPayload to a URL. With a secret,
+// requests are signed: HeaderSignature is "sha256=" followed by the hex
+// HMAC-SHA256 of HeaderTimestamp, a dot and the body.
+type Webhook struct {
+	URL    string
+	Secret []byte
+
+	// Language of status messages (defaults to English)
+	Language evatr.Language
+
+	// Defaults to a client with a 30 second timeout
+	HTTPClient *http.Client
+}
+
+var defaultWebhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// Notify implements Notifier. Answers other than 2xx fail; 4xx answers
+// except 408 and 429 wrap ErrPermanent.
+func (w *Webhook) Notify(ctx context.Context, events []evatr.ChangeEvent) error {
+	lang := w.Language
+	if lang == "" {
+		lang = evatr.LanguageEnglish
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(WebhookPayload{Changes: NewChanges(events, lang), SentAt: now.UTC()})
+	if err != nil {
+		return fmt.Errorf("%w: encode payload: %w", ErrPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.Secret) > 0 {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, body))
+	}
+
+	client := w.HTTPClient
+	if client == nil {
+		client = defaultWebhookClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch code := resp.StatusCode; {
+	case code >= 200 && code < 300:
+		return nil
+	case code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: webhook: HTTP %d", ErrPermanent, code)
+	default:
+		return fmt.Errorf("notify: webhook: HTTP %d", code)
+	}
+}
+
+// Sign returns the signature of a webhook body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature of a received webhook request with
+// the given body. A positive tolerance rejects timestamps further from the
+// current time, to prevent replays.
+func VerifySignature(secret []byte, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp := header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or invalid timestamp", ErrInvalidSignature)
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(seconds, 0)).Abs(); age > tolerance {
+			return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+		}
+	}
+
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
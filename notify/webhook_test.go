@@ -0,0 +1,109 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebhook tests signed webhook requests
+func TestWebhook(t *testing.T) {
+	secret := []byte("s3cret")
+
+	var payload notify.WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := notify.VerifySignature(secret, r.Header, body, time.Minute); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(body, &payload))
+	}))
+	defer srv.Close()
+
+	webhook := &notify.Webhook{URL: srv.URL, Secret: secret}
+	require.NoError(t, webhook.Notify(t.Context(), testEvents()))
+
+	require.Len(t, payload.Changes, 2)
+	first := payload.Changes[0]
+	assert.Equal(t, "ATU12345678", first.VATID)
+	assert.Equal(t, "evatr-2001", string(first.NewStatus))
+	assert.Equal(t, "The requested VAT ID is not assigned at the time of the request.", first.NewMessage)
+	assert.True(t, first.Invalidated)
+	assert.True(t, checkedAt.Equal(first.CheckedAt))
+	assert.Equal(t, []notify.FieldChange{{Field: "street", Old: "A", New: "B"}}, payload.Changes[1].Fields)
+	assert.WithinDuration(t, time.Now(), payload.SentAt, time.Minute)
+
+	// A wrong secret is rejected for good.
+	webhook.Secret = []byte("wrong")
+	assert.ErrorIs(t, webhook.Notify(t.Context(), testEvents()), notify.ErrPermanent)
+}
+
+// TestWebhookStatus tests which answers are permanent failures
+func TestWebhookStatus(t *testing.T) {
+	tests := []struct {
+		code      int
+		ok        bool
+		permanent bool
+	}{
+		{http.StatusOK, true, false},
+		{http.StatusNoContent, true, false},
+		{http.StatusBadRequest, false, true},
+		{http.StatusNotFound, false, true},
+		{http.StatusRequestTimeout, false, false},
+		{http.StatusTooManyRequests, false, false},
+		{http.StatusInternalServerError, false, false},
+		{http.StatusBadGateway, false, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(strconv.Itoa(tc.code), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.code)
+			}))
+			defer srv.Close()
+
+			err := (&notify.Webhook{URL: srv.URL}).Notify(t.Context(), testEvents())
+			if tc.ok {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tc.permanent, errors.Is(err, notify.ErrPermanent))
+		})
+	}
+}
+
+// TestVerifySignature tests tampered and replayed requests are rejected
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("s3cret")
+	body := []byte(`{"changes":[]}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	header := http.Header{}
+	header.Set(notify.HeaderTimestamp, timestamp)
+	header.Set(notify.HeaderSignature, notify.Sign(secret, timestamp, body))
+	assert.NoError(t, notify.VerifySignature(secret, header, body, time.Minute))
+
+	assert.ErrorIs(t, notify.VerifySignature(secret, header, []byte(`{"changes":null}`), time.Minute), notify.ErrInvalidSignature)
+	assert.ErrorIs(t, notify.VerifySignature([]byte("other"), header, body, time.Minute), notify.ErrInvalidSignature)
+
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	header.Set(notify.HeaderTimestamp, old)
+	header.Set(notify.HeaderSignature, notify.Sign(secret, old, body))
+	assert.ErrorIs(t, notify.VerifySignature(secret, header, body, time.Minute), notify.ErrInvalidSignature)
+	assert.NoError(t, notify.VerifySignature(secret, header, body, 0))
+
+	header.Del(notify.HeaderTimestamp)
+	assert.ErrorIs(t, notify.VerifySignature(secret, header, body, 0), notify.ErrInvalidSignature)
+}
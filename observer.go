@@ -0,0 +1,139 @@
+package evatr
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Operation names reported to observers.
+const (
+	OperationValidate       = "validate"
+	OperationStatusMessages = "status_messages"
+	OperationMemberStates   = "member_states"
+)
+
+// Operation describes a call of a public Client method.
+type Operation struct {
+	// One of the Operation* constants
+	Name string
+
+	// Whether a validation includes company data
+	Qualified bool
+
+	// Country code of the requested VAT ID (validations only)
+	CountryCode string
+}
+
+// OperationResult describes the outcome of an operation.
+type OperationResult struct {
+	// eVatR status of the response or error, if any
+	Status StatusCode
+
+	// Error returned to the caller
+	Err error
+
+	// Time spent in the operation, including retries and waiting
+	Duration time.Duration
+
+	// Whether the result was served from the cache
+	CacheHit bool
+
+	// Member states returned by GetEUMemberStates
+	MemberStates []EUMemberState
+}
+
+// RequestInfo describes a single HTTP request (attempt) to the API.
+type RequestInfo struct {
+	Method string
+	Path   string
+
+	// Attempt number, starting at 1
+	Attempt int
+}
+
+// RequestResult describes the outcome of an HTTP request.
+type RequestResult struct {
+	// HTTP status code, 0 if no response was received
+	StatusCode int
+
+	// eVatR status of the response, if any
+	Status StatusCode
+
+	// Error of the attempt
+	Err error
+
+	Duration time.Duration
+}
+
+// Observer is notified about operations and HTTP requests, e.g. to record
+// metrics or traces. Both start methods return a context passed down to
+// nested calls and a function called with the result.
+//
+// Observers must not retain VAT IDs or company data; the events carry none.
+type Observer interface {
+	StartOperation(ctx context.Context, op Operation) (context.Context, func(OperationResult))
+	StartRequest(ctx context.Context, req RequestInfo) (context.Context, func(RequestResult))
+}
+
+// WithObserver adds an observer. It can be given multiple times.
+func WithObserver(observer Observer) Option {
+	return func(c *Client) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
+// startOperation notifies all observers about an operation.
+func (c *Client) startOperation(ctx context.Context, op Operation) (context.Context, func(OperationResult)) {
+	if len(c.observers) == 0 {
+		return ctx, func(OperationResult) {}
+	}
+
+	start := time.Now()
+	finishers := make([]func(OperationResult), len(c.observers))
+	for i, o := range c.observers {
+		ctx, finishers[i] = o.StartOperation(ctx, op)
+	}
+
+	return ctx, func(result OperationResult) {
+		result.Duration = time.Since(start)
+		if result.Status == "" {
+			result.Status = errorStatus(result.Err)
+		}
+		for i := len(finishers) - 1; i >= 0; i-- {
+			finishers[i](result)
+		}
+	}
+}
+
+// startRequest notifies all observers about an HTTP request.
+func (c *Client) startRequest(ctx context.Context, req RequestInfo) (context.Context, func(RequestResult)) {
+	if len(c.observers) == 0 {
+		return ctx, func(RequestResult) {}
+	}
+
+	start := time.Now()
+	finishers := make([]func(RequestResult), len(c.observers))
+	for i, o := range c.observers {
+		ctx, finishers[i] = o.StartRequest(ctx, req)
+	}
+
+	return ctx, func(result RequestResult) {
+		result.Duration = time.Since(start)
+		if result.Status == "" {
+			result.Status = errorStatus(result.Err)
+		}
+		for i := len(finishers) - 1; i >= 0; i-- {
+			finishers[i](result)
+		}
+	}
+}
+
+// errorStatus returns the eVatR status of an API error.
+func errorStatus(err error) StatusCode {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+// Package otelevatr instruments the eVatR client with OpenTelemetry.
+//
+// Every client operation gets a span with a child span per HTTP request, and
+// counters and histograms are recorded for operations and requests:
+//
+//	client := evatr.NewClient(
+//		evatr.WithObserver(otelevatr.NewObserver()),
+//	)
+//
+// Attributes carry the requested country code but never full VAT IDs or
+// company data.
+package otelevatr
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/hostwithquantum/go-evatr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of tracer and meter.
+const ScopeName = "github.com/hostwithquantum/go-evatr/otelevatr"
+
+// Attribute keys.
+const (
+	AttrOperation   = attribute.Key("evatr.operation")
+	AttrEndpoint    = attribute.Key("evatr.endpoint")
+	AttrStatus      = attribute.Key("evatr.status")
+	AttrQualified   = attribute.Key("evatr.qualified")
+	AttrCountryCode = attribute.Key("evatr.requested_country")
+	AttrOutcome     = attribute.Key("evatr.outcome")
+	AttrCacheHit    = attribute.Key("evatr.cache_hit")
+	AttrAttempt     = attribute.Key("evatr.attempt")
+	AttrHTTPMethod  = attribute.Key("http.request.method")
+	AttrHTTPStatus  = attribute.Key("http.response.status_code")
+)
+
+// Outcomes recorded in AttrOutcome.
+const (
+	OutcomeValid     = "valid"
+	OutcomeInvalid   = "invalid"
+	OutcomeSuccess   = "success"
+	OutcomeTransient = "transient"
+	OutcomeError     = "error"
+)
+
+// Option configures the observer.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider (defaults to the global one).
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider (defaults to the global one).
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Observer implements evatr.Observer with OpenTelemetry.
+type Observer struct {
+	tracer trace.Tracer
+
+	operations        metric.Int64Counter
+	operationDuration metric.Float64Histogram
+	requests          metric.Int64Counter
+	requestDuration   metric.Float64Histogram
+}
+
+var _ evatr.Observer = (*Observer)(nil)
+
+// NewObserver returns an observer recording spans and metrics.
+func NewObserver(opts ...Option) *Observer {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	o := &Observer{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	// Instrument creation only fails for invalid names, fall back to no-ops.
+	var err error
+	if o.operations, err = meter.Int64Counter("evatr.client.operations",
+		metric.WithDescription("Client operations by outcome")); err != nil {
+		otel.Handle(err)
+	}
+	if o.operationDuration, err = meter.Float64Histogram("evatr.client.operation.duration",
+		metric.WithDescription("Duration of client operations including retries"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if o.requests, err = meter.Int64Counter("evatr.client.requests",
+		metric.WithDescription("HTTP requests sent to the eVatR API")); err != nil {
+		otel.Handle(err)
+	}
+	if o.requestDuration, err = meter.Float64Histogram("evatr.client.request.duration",
+		metric.WithDescription("Duration of HTTP requests to the eVatR API"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+
+	return o
+}
+
+// StartOperation implements evatr.Observer.
+func (o *Observer) StartOperation(ctx context.Context, op evatr.Operation) (context.Context, func(evatr.OperationResult)) {
+	attrs := []attribute.KeyValue{AttrOperation.String(op.Name)}
+	if op.Name == evatr.OperationValidate {
+		attrs = append(attrs, AttrQualified.Bool(op.Qualified))
+		if op.CountryCode != "" {
+			attrs = append(attrs, AttrCountryCode.String(op.CountryCode))
+		}
+	}
+
+	ctx, span := o.tracer.Start(ctx, "evatr."+op.Name, trace.WithAttributes(attrs...))
+
+	return ctx, func(result evatr.OperationResult) {
+		outcome := operationOutcome(result)
+		resultAttrs := []attribute.KeyValue{
+			AttrOutcome.String(outcome),
+			AttrCacheHit.Bool(result.CacheHit),
+		}
+		if result.Status != "" {
+			resultAttrs = append(resultAttrs, AttrStatus.String(string(result.Status)))
+		}
+
+		span.SetAttributes(resultAttrs...)
+		if result.Err != nil {
+			recordError(span, result.Err)
+		}
+		span.End()
+
+		set := metric.WithAttributes(append(attrs, resultAttrs...)...)
+		o.operations.Add(ctx, 1, set)
+		o.operationDuration.Record(ctx, result.Duration.Seconds(), set)
+	}
+}
+
+// StartRequest implements evatr.Observer.
+func (o *Observer) StartRequest(ctx context.Context, req evatr.RequestInfo) (context.Context, func(evatr.RequestResult)) {
+	attrs := []attribute.KeyValue{
+		AttrHTTPMethod.String(req.Method),
+		AttrEndpoint.String(req.Path),
+	}
+
+	ctx, span := o.tracer.Start(ctx, req.Method+" "+req.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, AttrAttempt.Int(req.Attempt))...),
+	)
+
+	return ctx, func(result evatr.RequestResult) {
+		var resultAttrs []attribute.KeyValue
+		if result.StatusCode != 0 {
+			resultAttrs = append(resultAttrs, AttrHTTPStatus.Int(result.StatusCode))
+		}
+		if result.Status != "" {
+			resultAttrs = append(resultAttrs, AttrStatus.String(string(result.Status)))
+		}
+
+		span.SetAttributes(resultAttrs...)
+		if result.Err != nil {
+			recordError(span, result.Err)
+		}
+		span.End()
+
+		set := metric.WithAttributes(append(attrs, resultAttrs...)...)
+		o.requests.Add(ctx, 1, set)
+		o.requestDuration.Record(ctx, result.Duration.Seconds(), set)
+	}
+}
+
+// operationOutcome classifies an operation result.
+func operationOutcome(result evatr.OperationResult) string {
+	if result.Err == nil {
+		switch result.Status {
+		case "":
+			return OutcomeSuccess
+		case evatr.StatusValid, evatr.StatusValidWithSpecialCase:
+			return OutcomeValid
+		default:
+			return OutcomeInvalid
+		}
+	}
+
+	if evatr.IsRetryable(result.Err) {
+		return OutcomeTransient
+	}
+	if evatr.IsEvatrErr(result.Err) {
+		return OutcomeInvalid
+	}
+	return OutcomeError
+}
+
+// recordError marks the span as failed. Pre-flight errors of the client
+// quote the VAT ID, so only API errors are recorded with their message.
+func recordError(span trace.Span, err error) {
+	var apiErr *evatr.Error
+	if errors.As(err, &apiErr) {
+		span.RecordError(apiErr)
+		span.SetStatus(codes.Error, apiErr.Error())
+		return
+	}
+
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &urlErr):
+		span.SetStatus(codes.Error, "request failed")
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.SetStatus(codes.Error, "operation failed")
+	}
+}
@@ -0,0 +1,154 @@
+package otelevatr_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/hostwithquantum/go-evatr/otelevatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setup(t *testing.T, opts ...evatr.Option) (*evatrtest.Server, *evatr.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	srv := evatrtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	observer := otelevatr.NewObserver(
+		otelevatr.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelevatr.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	return srv, srv.Client(append(opts, evatr.WithObserver(observer))...), spans, reader
+}
+
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// TestTracing tests operation and request spans
+func TestTracing(t *testing.T) {
+	_, client, spans, _ := setup(t)
+
+	_, err := client.ValidateVATQualified(t.Context(), "DE123456789", "ATU12345678", "Test GmbH", "Wien", "", "")
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+
+	request, operation := ended[0], ended[1]
+	assert.Equal(t, "evatr.validate", operation.Name())
+	assert.Equal(t, "POST /v1/abfrage", request.Name())
+	assert.Equal(t, trace.SpanKindClient, request.SpanKind())
+	assert.Equal(t, operation.SpanContext().SpanID(), request.Parent().SpanID())
+
+	opAttrs := attrs(operation.Attributes())
+	assert.Equal(t, "validate", opAttrs[otelevatr.AttrOperation].AsString())
+	assert.Equal(t, "AT", opAttrs[otelevatr.AttrCountryCode].AsString())
+	assert.True(t, opAttrs[otelevatr.AttrQualified].AsBool())
+	assert.Equal(t, string(evatr.StatusValid), opAttrs[otelevatr.AttrStatus].AsString())
+	assert.Equal(t, otelevatr.OutcomeValid, opAttrs[otelevatr.AttrOutcome].AsString())
+
+	reqAttrs := attrs(request.Attributes())
+	assert.Equal(t, int64(200), reqAttrs[otelevatr.AttrHTTPStatus].AsInt64())
+	assert.Equal(t, int64(1), reqAttrs[otelevatr.AttrAttempt].AsInt64())
+
+	for _, span := range ended {
+		for _, kv := range span.Attributes() {
+			value := kv.Value.Emit()
+			assert.NotContains(t, value, "12345678", kv.Key)
+			assert.NotContains(t, value, "Test GmbH", kv.Key)
+		}
+	}
+}
+
+// TestTracingErrors tests error status, retries and redaction of errors
+func TestTracingErrors(t *testing.T) {
+	srv, client, spans, _ := setup(t,
+		evatr.WithPreflight(),
+		evatr.WithRetryPolicy(evatr.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+
+	srv.SetResponse("ATU13585627", evatrtest.Response{Status: evatr.StatusServiceUnavailable1})
+	_, err := client.ValidateVAT(t.Context(), "DE136695976", "ATU13585627")
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+
+	operation := ended[2]
+	assert.Equal(t, codes.Error, operation.Status().Code)
+	assert.Equal(t, otelevatr.OutcomeTransient, attrs(operation.Attributes())[otelevatr.AttrOutcome].AsString())
+	assert.Equal(t, int64(2), attrs(ended[1].Attributes())[otelevatr.AttrAttempt].AsInt64())
+
+	// Pre-flight errors quote the VAT ID and must not end up in the span.
+	spans.Reset()
+	_, err = client.ValidateVAT(t.Context(), "DE136695976", "ATU1234")
+	require.Error(t, err)
+
+	ended = spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.NotContains(t, ended[0].Status().Description, "ATU1234")
+	assert.Empty(t, ended[0].Events())
+}
+
+// TestMetrics tests the recorded instruments
+func TestMetrics(t *testing.T) {
+	srv, client, _, reader := setup(t, evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy))
+
+	for range 2 {
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+	}
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+	_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+	require.Error(t, err)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+	require.Contains(t, metrics, "evatr.client.operation.duration")
+	require.Contains(t, metrics, "evatr.client.request.duration")
+
+	operations := metrics["evatr.client.operations"].(metricdata.Sum[int64])
+	counts := make(map[string]int64)
+	for _, point := range operations.DataPoints {
+		outcome, _ := point.Attributes.Value(otelevatr.AttrOutcome)
+		cacheHit, _ := point.Attributes.Value(otelevatr.AttrCacheHit)
+		counts[outcome.AsString()+"/"+cacheHit.Emit()] += point.Value
+	}
+	assert.Equal(t, map[string]int64{"valid/false": 1, "valid/true": 1, "invalid/false": 1}, counts)
+
+	requests := metrics["evatr.client.requests"].(metricdata.Sum[int64])
+	var total int64
+	for _, point := range requests.DataPoints {
+		total += point.Value
+		for _, kv := range point.Attributes.ToSlice() {
+			assert.False(t, strings.Contains(kv.Value.Emit(), "ATU"), kv.Key)
+		}
+	}
+	assert.Equal(t, int64(2), total)
+}
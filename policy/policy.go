@@ -0,0 +1,353 @@
+// Package policy decides how to treat a customer after a VAT ID validation,
+// e.g. whether an invoice may apply the reverse charge.
+//
+// Rules are declared in YAML or JSON and evaluated in order; the first rule
+// whose conditions all match decides:
+//
+//	rules:
+//	  - name: confirmed
+//	    when: {status: [evatr-0000], confirmed: true}
+//	    action: accept
+//	    reason: VAT ID and company data confirmed
+//	  - name: unavailable
+//	    when: {category: [transient]}
+//	    action: review
+//	  - name: not-assigned
+//	    when: {status: [evatr-2001]}
+//	    action: reject
+//	default:
+//	  action: review
+//	  reason: no rule matched
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is the outcome of a decision.
+type Action string
+
+const (
+	// Accept the VAT ID, e.g. invoice with reverse charge
+	Accept Action = "accept"
+
+	// Hold for manual review
+	Review Action = "review"
+
+	// Reject the VAT ID, e.g. invoice with VAT
+	Reject Action = "reject"
+)
+
+func (a Action) valid() bool {
+	return a == Accept || a == Review || a == Reject
+}
+
+// Rule decides with Action when all conditions in When match.
+type Rule struct {
+	Name   string    `yaml:"name" json:"name"`
+	When   Condition `yaml:"when" json:"when"`
+	Action Action    `yaml:"action" json:"action"`
+	Reason string    `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// Condition matches a validation outcome. Empty fields match anything; a
+// list matches if any of its values matches.
+type Condition struct {
+	// Status of the response or the API error
+	Status []evatr.StatusCode `yaml:"status,omitempty" json:"status,omitempty"`
+
+	// Category of the error, see evatr.Classify
+	Category []evatr.Category `yaml:"category,omitempty" json:"category,omitempty"`
+
+	// Whether the validation failed with an error instead of a response
+	Error *bool `yaml:"error,omitempty" json:"error,omitempty"`
+
+	// Overall state of the compared company data
+	Match []evatr.MatchState `yaml:"match,omitempty" json:"match,omitempty"`
+
+	// Whether the company data is confirmed, see evatr.QualifiedSummary
+	Confirmed *bool `yaml:"confirmed,omitempty" json:"confirmed,omitempty"`
+
+	// Verification results per field, e.g. {street: [B, D]}
+	Fields map[evatr.QualifiedField][]evatr.VerificationResult `yaml:"fields,omitempty" json:"fields,omitempty"`
+
+	// Whether the VAT ID was valid on the date of the input, see
+	// evatr.ValidationResponse.ValidityOn
+	ValidOnDate *bool `yaml:"valid_on_date,omitempty" json:"valid_on_date,omitempty"`
+}
+
+// Decision is the result of Decide.
+type Decision struct {
+	Action Action
+
+	// Name of the deciding rule, empty for the default
+	Rule string
+
+	// Reason of the rule followed by the facts that matched
+	Reasons []string
+}
+
+// Policy is an ordered list of rules with a default decision.
+type Policy struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+
+	// Used when no rule matches
+	Default Fallback `yaml:"default" json:"default"`
+
+	// Fields that must match for Confirmed (defaults to evatr.DefaultMatchPolicy)
+	RequiredFields []evatr.QualifiedField `yaml:"required_fields,omitempty" json:"required_fields,omitempty"`
+}
+
+// Fallback is the decision when no rule matches.
+type Fallback struct {
+	// Defaults to Review
+	Action Action `yaml:"action" json:"action"`
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// Input is a validation outcome to decide on.
+type Input struct {
+	// Response of the validation, nil if it failed
+	Response *evatr.ValidationResponse
+
+	// Error of the validation
+	Err error
+
+	// Date to check the validity dates against, e.g. the invoice date;
+	// defaults to the request timestamp of the response, then to now
+	Date time.Time
+}
+
+// Parse parses a policy from YAML or JSON.
+func Parse(data []byte) (*Policy, error) {
+	return Load(bytes.NewReader(data))
+}
+
+// Load reads a policy in YAML or JSON. Unknown keys, actions and field names
+// are rejected.
+func Load(r io.Reader) (*Policy, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var p Policy
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// LoadFile reads a policy from a YAML or JSON file.
+func LoadFile(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks the actions, fields and verification results of all rules.
+func (p *Policy) Validate() error {
+	if p.Default.Action != "" && !p.Default.Action.valid() {
+		return fmt.Errorf("policy: default: unknown action %q", p.Default.Action)
+	}
+	for _, field := range p.RequiredFields {
+		if !slices.Contains(evatr.QualifiedFields, field) {
+			return fmt.Errorf("policy: required_fields: unknown field %q", field)
+		}
+	}
+
+	for i, rule := range p.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if !rule.Action.valid() {
+			return fmt.Errorf("policy: rule %s: unknown action %q", name, rule.Action)
+		}
+		for field, results := range rule.When.Fields {
+			if !slices.Contains(evatr.QualifiedFields, field) {
+				return fmt.Errorf("policy: rule %s: unknown field %q", name, field)
+			}
+			for _, result := range results {
+				switch result {
+				case evatr.VerificationMatch, evatr.VerificationMismatch,
+					evatr.VerificationNotRequested, evatr.VerificationNotProvided:
+				default:
+					return fmt.Errorf("policy: rule %s: field %s: unknown result %q", name, field, result)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Decide returns the decision of the first matching rule, or the default.
+func (p *Policy) Decide(in Input) Decision {
+	facts := p.facts(in)
+
+	for _, rule := range p.Rules {
+		reasons, ok := rule.When.match(facts)
+		if !ok {
+			continue
+		}
+		if rule.Reason != "" {
+			reasons = append([]string{rule.Reason}, reasons...)
+		}
+		return Decision{Action: rule.Action, Rule: rule.Name, Reasons: reasons}
+	}
+
+	d := Decision{Action: p.Default.Action}
+	if d.Action == "" {
+		d.Action = Review
+	}
+	if p.Default.Reason != "" {
+		d.Reasons = []string{p.Default.Reason}
+	}
+	return d
+}
+
+// Evaluate decides on the result of a ValidateVAT* call.
+func (p *Policy) Evaluate(resp *evatr.ValidationResponse, err error) Decision {
+	return p.Decide(Input{Response: resp, Err: err})
+}
+
+// facts are the properties of an input that conditions match against.
+type facts struct {
+	resp     *evatr.ValidationResponse
+	err      error
+	status   evatr.StatusCode
+	category evatr.Category
+	summary  evatr.QualifiedSummary
+	date     time.Time
+}
+
+func (p *Policy) facts(in Input) facts {
+	f := facts{resp: in.Response, err: in.Err, date: in.Date}
+
+	if in.Err != nil {
+		f.category = evatr.Classify(in.Err)
+		var apiErr *evatr.Error
+		if errors.As(in.Err, &apiErr) {
+			f.status = apiErr.Status
+		}
+	}
+
+	if in.Response != nil {
+		matchPolicy := evatr.DefaultMatchPolicy
+		if len(p.RequiredFields) > 0 {
+			matchPolicy = evatr.MatchPolicy{Required: p.RequiredFields}
+		}
+		if f.status == "" {
+			f.status = in.Response.Status
+		}
+		f.summary = matchPolicy.Summarize(in.Response)
+		if f.date.IsZero() {
+			f.date = in.Response.RequestTimestamp.Time
+		}
+	}
+	if f.date.IsZero() {
+		f.date = time.Now()
+	}
+
+	return f
+}
+
+// match returns whether all conditions match and describes the matches.
+func (c Condition) match(f facts) ([]string, bool) {
+	var reasons []string
+
+	if len(c.Status) > 0 {
+		if !slices.Contains(c.Status, f.status) {
+			return nil, false
+		}
+		reasons = append(reasons, "status is "+string(f.status))
+	}
+
+	if len(c.Category) > 0 {
+		if f.err == nil || !slices.Contains(c.Category, f.category) {
+			return nil, false
+		}
+		reasons = append(reasons, "error is "+f.category.String())
+	}
+
+	if c.Error != nil {
+		if *c.Error != (f.err != nil) {
+			return nil, false
+		}
+		if f.err != nil {
+			reasons = append(reasons, "validation failed")
+		} else {
+			reasons = append(reasons, "validation succeeded")
+		}
+	}
+
+	if len(c.Match) > 0 || c.Confirmed != nil || len(c.Fields) > 0 {
+		if f.resp == nil {
+			return nil, false
+		}
+	}
+
+	if len(c.Match) > 0 {
+		if !slices.Contains(c.Match, f.summary.State) {
+			return nil, false
+		}
+		reasons = append(reasons, "company data is "+f.summary.State.String())
+	}
+
+	if c.Confirmed != nil {
+		if *c.Confirmed != f.summary.Confirmed {
+			return nil, false
+		}
+		if f.summary.Confirmed {
+			reasons = append(reasons, "company data is confirmed")
+		} else {
+			reasons = append(reasons, "company data is not confirmed")
+		}
+	}
+
+	for _, field := range evatr.QualifiedFields {
+		results, ok := c.Fields[field]
+		if !ok {
+			continue
+		}
+		result := f.resp.Result(field)
+		if result == "" {
+			result = evatr.VerificationNotRequested
+		}
+		if !slices.Contains(results, result) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("%s is %s", field, result))
+	}
+
+	if c.ValidOnDate != nil {
+		validity, reason := evatr.ValidityUnknown, "no response"
+		if f.resp != nil {
+			validity, reason = f.resp.ValidityOn(f.date)
+		}
+		valid := validity == evatr.ValidityValid
+		if reason == "" {
+			reason = "no validity dates"
+		}
+		reason = fmt.Sprintf("%s on %s (%s)", validity, f.date.Format(time.DateOnly), reason)
+		if *c.ValidOnDate != valid {
+			return nil, false
+		}
+		reasons = append(reasons, reason)
+	}
+
+	return reasons, true
+}
@@ -0,0 +1,227 @@
+package policy_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rules = `
+required_fields: [company_name, city]
+rules:
+  - name: unavailable
+    when: {category: [transient]}
+    action: review
+    reason: eVatR unavailable, retry later
+  - name: caller-error
+    when: {error: true}
+    action: reject
+  - name: confirmed
+    when: {status: [evatr-0000], confirmed: true}
+    action: accept
+    reason: VAT ID and company data confirmed
+  - name: street-mismatch
+    when:
+      status: [evatr-0000, evatr-2008]
+      fields: {street: [B]}
+    action: review
+  - name: valid-on-invoice-date
+    when: {status: [evatr-2006], valid_on_date: true}
+    action: accept
+  - name: not-assigned
+    when: {status: [evatr-2001, evatr-2006]}
+    action: reject
+default:
+  action: review
+  reason: no rule matched
+`
+
+func date(s string) evatr.Time {
+	t, err := evatr.ParseTime(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TestDecide tests the first matching rule decides
+func TestDecide(t *testing.T) {
+	p, err := policy.Parse([]byte(rules))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		in      policy.Input
+		action  policy.Action
+		rule    string
+		reasons []string
+	}{
+		{
+			name: "confirmed",
+			in: policy.Input{Response: &evatr.ValidationResponse{
+				Status:            evatr.StatusValid,
+				CompanyNameResult: evatr.VerificationMatch,
+				CityResult:        evatr.VerificationMatch,
+			}},
+			action:  policy.Accept,
+			rule:    "confirmed",
+			reasons: []string{"VAT ID and company data confirmed", "status is evatr-0000", "company data is confirmed"},
+		},
+		{
+			name: "simple validation",
+			in: policy.Input{Response: &evatr.ValidationResponse{
+				Status: evatr.StatusValid,
+			}},
+			action:  policy.Review,
+			reasons: []string{"no rule matched"},
+		},
+		{
+			name: "street mismatch",
+			in: policy.Input{Response: &evatr.ValidationResponse{
+				Status:            evatr.StatusValidWithSpecialCase,
+				CompanyNameResult: evatr.VerificationMatch,
+				StreetResult:      evatr.VerificationMismatch,
+				CityResult:        evatr.VerificationMatch,
+			}},
+			action:  policy.Review,
+			rule:    "street-mismatch",
+			reasons: []string{"status is evatr-2008", "street is B"},
+		},
+		{
+			name: "expired after invoice date",
+			in: policy.Input{
+				Response: &evatr.ValidationResponse{
+					Status:     evatr.StatusNoLongerValid,
+					ValidFrom:  date("2020-01-01"),
+					ValidUntil: date("2025-06-30"),
+				},
+				Date: time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC),
+			},
+			action:  policy.Accept,
+			rule:    "valid-on-invoice-date",
+			reasons: []string{"status is evatr-2006", "valid on 2025-06-30 (evatr-2006: valid from 2020-01-01 until 2025-06-30)"},
+		},
+		{
+			name: "expired before invoice date",
+			in: policy.Input{
+				Response: &evatr.ValidationResponse{
+					Status:     evatr.StatusNoLongerValid,
+					ValidFrom:  date("2020-01-01"),
+					ValidUntil: date("2025-06-30"),
+				},
+				Date: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC),
+			},
+			action:  policy.Reject,
+			rule:    "not-assigned",
+			reasons: []string{"status is evatr-2006"},
+		},
+		{
+			name:    "transient error",
+			in:      policy.Input{Err: &evatr.Error{StatusCode: 503, Status: evatr.StatusServiceUnavailable1}},
+			action:  policy.Review,
+			rule:    "unavailable",
+			reasons: []string{"eVatR unavailable, retry later", "error is transient"},
+		},
+		{
+			name:    "wrapped error",
+			in:      policy.Input{Err: fmt.Errorf("validate: %w", context.DeadlineExceeded)},
+			action:  policy.Review,
+			rule:    "unavailable",
+			reasons: []string{"eVatR unavailable, retry later", "error is transient"},
+		},
+		{
+			name:    "caller error",
+			in:      policy.Input{Err: evatr.ErrMissingCity},
+			action:  policy.Reject,
+			rule:    "caller-error",
+			reasons: []string{"validation failed"},
+		},
+		{
+			name:    "error with status",
+			in:      policy.Input{Err: &evatr.Error{StatusCode: 404, Status: evatr.StatusVATIDNotAssigned}},
+			action:  policy.Reject,
+			rule:    "caller-error",
+			reasons: []string{"validation failed"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := p.Decide(tc.in)
+			assert.Equal(t, tc.action, d.Action)
+			assert.Equal(t, tc.rule, d.Rule)
+			assert.Equal(t, tc.reasons, d.Reasons)
+		})
+	}
+}
+
+// TestDecideMatchState tests match states and a status from an API error
+func TestDecideMatchState(t *testing.T) {
+	p, err := policy.Parse([]byte(`{
+		"rules": [
+			{"name": "partial", "when": {"match": ["partial", "mismatch"]}, "action": "review"},
+			{"name": "not-assigned", "when": {"status": ["evatr-2001"]}, "action": "reject"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	d := p.Evaluate(&evatr.ValidationResponse{
+		Status:            evatr.StatusValid,
+		CompanyNameResult: evatr.VerificationMismatch,
+		CityResult:        evatr.VerificationMismatch,
+	}, nil)
+	assert.Equal(t, policy.Decision{Action: policy.Review, Rule: "partial", Reasons: []string{"company data is mismatch"}}, d)
+
+	d = p.Evaluate(nil, &evatr.Error{StatusCode: 404, Status: evatr.StatusVATIDNotAssigned})
+	assert.Equal(t, policy.Reject, d.Action)
+
+	d = p.Evaluate(&evatr.ValidationResponse{Status: evatr.StatusValid}, nil)
+	assert.Equal(t, policy.Decision{Action: policy.Review}, d)
+}
+
+// TestParseErrors tests invalid policies are rejected
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":      "rules: [{name: a, when: {staus: [evatr-0000]}, action: accept}]",
+		"unknown action":   "rules: [{name: a, action: allow}]",
+		"unknown default":  "default: {action: allow}",
+		"unknown category": "rules: [{name: a, when: {category: [flaky]}, action: review}]",
+		"unknown state":    "rules: [{name: a, when: {match: [perfect]}, action: review}]",
+		"unknown field":    "rules: [{name: a, when: {fields: {country: [A]}}, action: review}]",
+		"unknown result":   "rules: [{name: a, when: {fields: {street: [E]}}, action: review}]",
+		"unknown required": "required_fields: [country]",
+	}
+
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := policy.Parse([]byte(doc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestLoadFile tests loading a policy from a file
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(rules), 0o600))
+
+	p, err := policy.LoadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, p.Rules, 6)
+	assert.Equal(t, policy.Review, p.Default.Action)
+
+	_, err = policy.LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	empty, err := policy.Parse(nil)
+	require.NoError(t, err)
+	assert.Equal(t, policy.Review, empty.Decide(policy.Input{}).Action)
+}
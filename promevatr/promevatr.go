@@ -0,0 +1,224 @@
+// Package promevatr exposes Prometheus metrics of the eVatR client.
+//
+// A Collector is both a prometheus.Collector and an evatr.Observer:
+//
+//	collector := promevatr.NewCollector()
+//	prometheus.MustRegister(collector)
+//
+//	client := evatr.NewClient(evatr.WithObserver(collector))
+//
+// Labels carry endpoints, status codes and country codes but never VAT IDs
+// or company data.
+package promevatr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace prefixes all metric names.
+const DefaultNamespace = "evatr"
+
+// noValue is the label value for a missing HTTP or eVatR status.
+const noValue = "none"
+
+// Option configures the collector.
+type Option func(*config)
+
+type config struct {
+	namespace   string
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+// WithNamespace sets the metric name prefix (defaults to DefaultNamespace).
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithConstLabels adds labels to all metrics, e.g. to tell clients apart.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets sets the buckets of the latency histograms in seconds
+// (defaults to prometheus.DefBuckets).
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// Collector records client metrics. Register it with a prometheus.Registerer
+// and pass it to the client with evatr.WithObserver.
+type Collector struct {
+	requests            *prometheus.CounterVec
+	requestDuration     *prometheus.HistogramVec
+	retries             *prometheus.CounterVec
+	operations          *prometheus.CounterVec
+	operationDuration   *prometheus.HistogramVec
+	cacheHits           *prometheus.CounterVec
+	memberStates        *prometheus.GaugeVec
+	memberStatesUpdated prometheus.Gauge
+}
+
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+	_ evatr.Observer       = (*Collector)(nil)
+)
+
+// NewCollector returns a collector with the following metrics:
+//
+//   - evatr_requests_total{endpoint,code,status}: HTTP requests by HTTP and eVatR status
+//   - evatr_request_duration_seconds{endpoint}: latency of HTTP requests
+//   - evatr_retries_total{endpoint}: requests that were retries of a failed attempt
+//   - evatr_operations_total{operation,status}: client calls by eVatR status
+//   - evatr_operation_duration_seconds{operation}: latency of client calls including retries
+//   - evatr_cache_hits_total{operation}: client calls served from the cache
+//   - evatr_member_state_available{country}: last observed VIES availability (1 or 0)
+//   - evatr_member_states_last_update_timestamp_seconds: time of the last availability update
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{
+		namespace: DefaultNamespace,
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "requests_total",
+			Help:        "HTTP requests sent to the eVatR API by HTTP status code and eVatR status.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "code", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of HTTP requests to the eVatR API.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{"endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "retries_total",
+			Help:        "HTTP requests retrying a failed attempt.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "operations_total",
+			Help:        "Client operations by eVatR status.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation", "status"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Name:        "operation_duration_seconds",
+			Help:        "Duration of client operations including retries and waiting.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{"operation"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "cache_hits_total",
+			Help:        "Client operations served from the cache.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation"}),
+		memberStates: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Name:        "member_state_available",
+			Help:        "Whether VIES of a member state was available at the last GetEUMemberStates call.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"country"}),
+		memberStatesUpdated: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Name:        "member_states_last_update_timestamp_seconds",
+			Help:        "Time of the last successful GetEUMemberStates call.",
+			ConstLabels: cfg.constLabels,
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c.metrics() {
+		m.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.metrics() {
+		m.Collect(ch)
+	}
+}
+
+func (c *Collector) metrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.requests,
+		c.requestDuration,
+		c.retries,
+		c.operations,
+		c.operationDuration,
+		c.cacheHits,
+		c.memberStates,
+		c.memberStatesUpdated,
+	}
+}
+
+// StartOperation implements evatr.Observer.
+func (c *Collector) StartOperation(ctx context.Context, op evatr.Operation) (context.Context, func(evatr.OperationResult)) {
+	return ctx, func(result evatr.OperationResult) {
+		c.operations.WithLabelValues(op.Name, statusLabel(result.Status)).Inc()
+		c.operationDuration.WithLabelValues(op.Name).Observe(result.Duration.Seconds())
+		if result.CacheHit {
+			c.cacheHits.WithLabelValues(op.Name).Inc()
+		}
+
+		if op.Name == evatr.OperationMemberStates && result.Err == nil {
+			for _, state := range result.MemberStates {
+				c.memberStates.WithLabelValues(state.Alpha2).Set(boolValue(state.Available))
+			}
+			c.memberStatesUpdated.Set(float64(time.Now().UnixNano()) / 1e9)
+		}
+	}
+}
+
+// StartRequest implements evatr.Observer.
+func (c *Collector) StartRequest(ctx context.Context, req evatr.RequestInfo) (context.Context, func(evatr.RequestResult)) {
+	if req.Attempt > 1 {
+		c.retries.WithLabelValues(req.Path).Inc()
+	}
+
+	return ctx, func(result evatr.RequestResult) {
+		code := noValue
+		if result.StatusCode != 0 {
+			code = strconv.Itoa(result.StatusCode)
+		}
+		c.requests.WithLabelValues(req.Path, code, statusLabel(result.Status)).Inc()
+		c.requestDuration.WithLabelValues(req.Path).Observe(result.Duration.Seconds())
+	}
+}
+
+func statusLabel(status evatr.StatusCode) string {
+	if status == "" {
+		return noValue
+	}
+	return string(status)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package promevatr_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/hostwithquantum/go-evatr/promevatr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollector tests the recorded metrics
+func TestCollector(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	collector := promevatr.NewCollector()
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	client := srv.Client(
+		evatr.WithObserver(collector),
+		evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy),
+		evatr.WithRetryPolicy(evatr.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+
+	for range 2 {
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+	}
+
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+	_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU99999999")
+	require.Error(t, err)
+
+	srv.SetResponse("ATU88888888", evatrtest.Response{Status: evatr.StatusProcessingError1})
+	_, err = client.ValidateVAT(t.Context(), "DE123456789", "ATU88888888")
+	require.Error(t, err)
+
+	srv.SetAvailable("FR", false)
+	_, err = client.GetEUMemberStates(t.Context())
+	require.NoError(t, err)
+
+	expected := `
+# HELP evatr_requests_total HTTP requests sent to the eVatR API by HTTP status code and eVatR status.
+# TYPE evatr_requests_total counter
+evatr_requests_total{code="200",endpoint="/v1/abfrage",status="evatr-0000"} 1
+evatr_requests_total{code="200",endpoint="/v1/info/eu_mitgliedstaaten",status="none"} 1
+evatr_requests_total{code="404",endpoint="/v1/abfrage",status="evatr-2001"} 1
+evatr_requests_total{code="500",endpoint="/v1/abfrage",status="evatr-2004"} 2
+# HELP evatr_retries_total HTTP requests retrying a failed attempt.
+# TYPE evatr_retries_total counter
+evatr_retries_total{endpoint="/v1/abfrage"} 1
+# HELP evatr_operations_total Client operations by eVatR status.
+# TYPE evatr_operations_total counter
+evatr_operations_total{operation="member_states",status="none"} 1
+evatr_operations_total{operation="validate",status="evatr-0000"} 2
+evatr_operations_total{operation="validate",status="evatr-2001"} 1
+evatr_operations_total{operation="validate",status="evatr-2004"} 1
+# HELP evatr_cache_hits_total Client operations served from the cache.
+# TYPE evatr_cache_hits_total counter
+evatr_cache_hits_total{operation="validate"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"evatr_requests_total", "evatr_retries_total", "evatr_operations_total", "evatr_cache_hits_total"))
+
+	assert.Equal(t, 28, testutil.CollectAndCount(collector, "evatr_member_state_available"))
+	assert.Equal(t, 0.0, availability(t, registry, "FR"))
+	assert.Equal(t, 1.0, availability(t, registry, "DE"))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "evatr_request_duration_seconds"))
+}
+
+// availability returns the member state gauge of a country.
+func availability(t *testing.T, registry *prometheus.Registry, country string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "evatr_member_state_available" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if m.GetLabel()[0].GetValue() == country {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	t.Fatalf("no gauge for %s", country)
+	return 0
+}
+
+// TestCollectorOptions tests namespace and constant labels
+func TestCollectorOptions(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	collector := promevatr.NewCollector(
+		promevatr.WithNamespace("billing_evatr"),
+		promevatr.WithConstLabels(prometheus.Labels{"client": "billing"}),
+		promevatr.WithBuckets([]float64{0.1, 1}),
+	)
+
+	_, err := srv.Client(evatr.WithObserver(collector)).GetStatusMessages(t.Context())
+	require.NoError(t, err)
+
+	expected := `
+# HELP billing_evatr_operations_total Client operations by eVatR status.
+# TYPE billing_evatr_operations_total counter
+billing_evatr_operations_total{client="billing",operation="status_messages",status="none"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "billing_evatr_operations_total"))
+
+	problems, err := testutil.CollectAndLint(collector)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
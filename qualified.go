@@ -0,0 +1,162 @@
+package evatr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// QualifiedField is a company data field of a qualified validation.
+type QualifiedField string
+
+// Fields compared by a qualified validation.
+const (
+	FieldCompanyName QualifiedField = "company_name"
+	FieldStreet      QualifiedField = "street"
+	FieldPostalCode  QualifiedField = "postal_code"
+	FieldCity        QualifiedField = "city"
+)
+
+// QualifiedFields lists all fields in the order of the API.
+var QualifiedFields = []QualifiedField{FieldCompanyName, FieldStreet, FieldPostalCode, FieldCity}
+
+// Result returns the verification result of a field.
+func (v *ValidationResponse) Result(field QualifiedField) VerificationResult {
+	switch field {
+	case FieldCompanyName:
+		return v.CompanyNameResult
+	case FieldStreet:
+		return v.StreetResult
+	case FieldPostalCode:
+		return v.PostalCodeResult
+	case FieldCity:
+		return v.CityResult
+	default:
+		return ""
+	}
+}
+
+// MatchState is the overall result of the compared company data.
+type MatchState int
+
+const (
+	// No company data was compared, e.g. for a simple validation
+	MatchNotRequested MatchState = iota
+
+	// All compared fields match
+	MatchFull
+
+	// Some compared fields match, others do not or were not provided
+	MatchPartial
+
+	// None of the compared fields match
+	MatchMismatch
+
+	// The member state provided none of the compared fields
+	MatchNotProvided
+)
+
+func (s MatchState) String() string {
+	switch s {
+	case MatchFull:
+		return "full_match"
+	case MatchPartial:
+		return "partial"
+	case MatchMismatch:
+		return "mismatch"
+	case MatchNotProvided:
+		return "not_provided"
+	default:
+		return "not_requested"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s MatchState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the names returned
+// by String.
+func (s *MatchState) UnmarshalText(text []byte) error {
+	for state := MatchNotRequested; state <= MatchNotProvided; state++ {
+		if state.String() == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("evatr: unknown match state %q", text)
+}
+
+// MatchPolicy decides when company data counts as confirmed.
+type MatchPolicy struct {
+	// Fields that must match (A); fields not listed may differ. Without
+	// fields, all requested fields must match.
+	Required []QualifiedField
+}
+
+// DefaultMatchPolicy requires the fields the API requires for a qualified
+// validation: company name and city.
+var DefaultMatchPolicy = MatchPolicy{
+	Required: []QualifiedField{FieldCompanyName, FieldCity},
+}
+
+// QualifiedSummary summarizes the verification results of a response.
+type QualifiedSummary struct {
+	State MatchState
+
+	// The VAT ID is valid and all fields required by the policy match
+	Confirmed bool
+
+	// Fields by result; fields not sent in the request are NotRequested
+	Matched      []QualifiedField
+	Mismatched   []QualifiedField
+	NotProvided  []QualifiedField
+	NotRequested []QualifiedField
+}
+
+// Summary summarizes the verification results with DefaultMatchPolicy.
+func (v *ValidationResponse) Summary() QualifiedSummary {
+	return DefaultMatchPolicy.Summarize(v)
+}
+
+// Summarize summarizes the verification results of a response.
+func (p MatchPolicy) Summarize(v *ValidationResponse) QualifiedSummary {
+	var s QualifiedSummary
+	for _, field := range QualifiedFields {
+		switch v.Result(field) {
+		case VerificationMatch:
+			s.Matched = append(s.Matched, field)
+		case VerificationMismatch:
+			s.Mismatched = append(s.Mismatched, field)
+		case VerificationNotProvided:
+			s.NotProvided = append(s.NotProvided, field)
+		default:
+			s.NotRequested = append(s.NotRequested, field)
+		}
+	}
+
+	switch {
+	case len(s.NotRequested) == len(QualifiedFields):
+		s.State = MatchNotRequested
+	case len(s.Mismatched) == 0 && len(s.NotProvided) == 0:
+		s.State = MatchFull
+	case len(s.Matched) > 0:
+		s.State = MatchPartial
+	case len(s.Mismatched) > 0:
+		s.State = MatchMismatch
+	default:
+		s.State = MatchNotProvided
+	}
+
+	s.Confirmed = v.IsValid() && s.State == MatchFull
+	if len(p.Required) > 0 {
+		s.Confirmed = v.IsValid() && s.State != MatchNotRequested
+		for _, field := range p.Required {
+			if !slices.Contains(s.Matched, field) {
+				s.Confirmed = false
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,109 @@
+package evatr_test
+
+import (
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQualifiedSummary tests the overall match state
+func TestQualifiedSummary(t *testing.T) {
+	const (
+		A = evatr.VerificationMatch
+		B = evatr.VerificationMismatch
+		C = evatr.VerificationNotRequested
+		D = evatr.VerificationNotProvided
+	)
+
+	tests := []struct {
+		name      string
+		status    evatr.StatusCode
+		results   [4]evatr.VerificationResult
+		state     evatr.MatchState
+		confirmed bool
+	}{
+		{"simple validation", evatr.StatusValid, [4]evatr.VerificationResult{}, evatr.MatchNotRequested, false},
+		{"full match", evatr.StatusValid, [4]evatr.VerificationResult{A, A, A, A}, evatr.MatchFull, true},
+		{"required fields only", evatr.StatusValid, [4]evatr.VerificationResult{A, C, C, A}, evatr.MatchFull, true},
+		{"street mismatch", evatr.StatusValid, [4]evatr.VerificationResult{A, B, A, A}, evatr.MatchPartial, true},
+		{"company mismatch", evatr.StatusValid, [4]evatr.VerificationResult{B, A, A, A}, evatr.MatchPartial, false},
+		{"mismatch", evatr.StatusValid, [4]evatr.VerificationResult{B, C, C, B}, evatr.MatchMismatch, false},
+		{"mismatch and not provided", evatr.StatusValid, [4]evatr.VerificationResult{B, D, D, D}, evatr.MatchMismatch, false},
+		{"not provided", evatr.StatusValid, [4]evatr.VerificationResult{D, D, D, D}, evatr.MatchNotProvided, false},
+		{"partly provided", evatr.StatusValid, [4]evatr.VerificationResult{A, D, D, A}, evatr.MatchPartial, true},
+		{"no longer valid", evatr.StatusNoLongerValid, [4]evatr.VerificationResult{A, A, A, A}, evatr.MatchFull, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &evatr.ValidationResponse{
+				Status:            tc.status,
+				CompanyNameResult: tc.results[0],
+				StreetResult:      tc.results[1],
+				PostalCodeResult:  tc.results[2],
+				CityResult:        tc.results[3],
+			}
+			summary := resp.Summary()
+			assert.Equal(t, tc.state, summary.State)
+			assert.Equal(t, tc.confirmed, summary.Confirmed)
+		})
+	}
+}
+
+// TestMatchPolicy tests field lists and custom policies
+func TestMatchPolicy(t *testing.T) {
+	resp := &evatr.ValidationResponse{
+		Status:            evatr.StatusValid,
+		CompanyNameResult: evatr.VerificationMatch,
+		StreetResult:      evatr.VerificationMismatch,
+		PostalCodeResult:  evatr.VerificationNotProvided,
+		CityResult:        evatr.VerificationMatch,
+	}
+
+	summary := resp.Summary()
+	assert.Equal(t, []evatr.QualifiedField{evatr.FieldCompanyName, evatr.FieldCity}, summary.Matched)
+	assert.Equal(t, []evatr.QualifiedField{evatr.FieldStreet}, summary.Mismatched)
+	assert.Equal(t, []evatr.QualifiedField{evatr.FieldPostalCode}, summary.NotProvided)
+	assert.Empty(t, summary.NotRequested)
+	assert.Equal(t, "partial", summary.State.String())
+	assert.True(t, summary.Confirmed)
+
+	strict := evatr.MatchPolicy{Required: evatr.QualifiedFields}
+	assert.False(t, strict.Summarize(resp).Confirmed)
+
+	lenient := evatr.MatchPolicy{Required: []evatr.QualifiedField{evatr.FieldCompanyName}}
+	assert.True(t, lenient.Summarize(resp).Confirmed)
+
+	// The zero policy requires all requested fields to match.
+	var zero evatr.MatchPolicy
+	assert.False(t, zero.Summarize(resp).Confirmed)
+	mismatched := &evatr.ValidationResponse{
+		Status:            evatr.StatusValid,
+		CompanyNameResult: evatr.VerificationMismatch,
+		StreetResult:      evatr.VerificationMismatch,
+		PostalCodeResult:  evatr.VerificationMismatch,
+		CityResult:        evatr.VerificationMismatch,
+	}
+	assert.Equal(t, evatr.MatchMismatch, zero.Summarize(mismatched).State)
+	assert.False(t, zero.Summarize(mismatched).Confirmed)
+	matched := &evatr.ValidationResponse{
+		Status:            evatr.StatusValid,
+		CompanyNameResult: evatr.VerificationMatch,
+		CityResult:        evatr.VerificationMatch,
+	}
+	assert.True(t, zero.Summarize(matched).Confirmed)
+
+	assert.Equal(t, evatr.VerificationMismatch, resp.Result(evatr.FieldStreet))
+}
+
+// TestMatchStateText tests the text encoding of match states
+func TestMatchStateText(t *testing.T) {
+	var state evatr.MatchState
+	assert.NoError(t, state.UnmarshalText([]byte("partial")))
+	assert.Equal(t, evatr.MatchPartial, state)
+	assert.Error(t, state.UnmarshalText([]byte("perfect")))
+
+	text, _ := evatr.MatchNotProvided.MarshalText()
+	assert.Equal(t, "not_provided", string(text))
+}
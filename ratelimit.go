@@ -0,0 +1,110 @@
+package evatr
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter controls how many requests the client sends. Implementations must
+// be safe for concurrent use, so a shared or distributed limiter can be used
+// by several clients.
+type Limiter interface {
+	// Acquire blocks until a request may be sent or ctx is done. The
+	// returned release function is called once the request has completed.
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// WithRateLimiter limits the requests sent by the client, including retries.
+func WithRateLimiter(limiter Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// RateLimiter is a token bucket combined with a limit of requests in flight.
+type RateLimiter struct {
+	rate     float64
+	burst    float64
+	inFlight chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rate requests per second with
+// bursts of up to burst requests, and at most maxInFlight concurrent requests.
+// A rate or maxInFlight of 0 disables the respective limit.
+func NewRateLimiter(rate float64, burst, maxInFlight int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// Acquire implements Limiter.
+func (l *RateLimiter) Acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// wait takes a token from the bucket, waiting for it if necessary.
+func (l *RateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	// Reserve the token right away, so waiting callers are served in order.
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
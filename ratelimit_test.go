@@ -0,0 +1,127 @@
+package evatr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingLimiter struct {
+	acquired atomic.Int32
+	released atomic.Int32
+}
+
+func (l *countingLimiter) Acquire(ctx context.Context) (func(), error) {
+	l.acquired.Add(1)
+	return func() { l.released.Add(1) }, nil
+}
+
+// TestRateLimiter tests the token bucket and the in-flight limit
+func TestRateLimiter(t *testing.T) {
+	t.Run("token bucket", func(t *testing.T) {
+		limiter := evatr.NewRateLimiter(20, 2, 0)
+
+		start := time.Now()
+		for range 4 {
+			release, err := limiter.Acquire(t.Context())
+			require.NoError(t, err)
+			release()
+		}
+		// two requests from the burst, two more at 20/s
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		limiter := evatr.NewRateLimiter(0, 0, 1)
+
+		release, err := limiter.Acquire(t.Context())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err = limiter.Acquire(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		release()
+		release, err = limiter.Acquire(t.Context())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("cancelled wait", func(t *testing.T) {
+		limiter := evatr.NewRateLimiter(0.1, 1, 0)
+
+		release, err := limiter.Acquire(t.Context())
+		require.NoError(t, err)
+		release()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		_, err = limiter.Acquire(ctx)
+		assert.Error(t, err)
+	})
+}
+
+// TestClientRateLimiter tests that the client applies the limiter to requests
+func TestClientRateLimiter(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+	}))
+	defer server.Close()
+
+	t.Run("max in flight", func(t *testing.T) {
+		client := evatr.NewClient(
+			evatr.WithBaseURL(server.URL),
+			evatr.WithRateLimiter(evatr.NewRateLimiter(0, 0, 2)),
+		)
+
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("custom limiter", func(t *testing.T) {
+		limiter := &countingLimiter{}
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRateLimiter(limiter))
+
+		_, err := client.GetEUMemberStates(t.Context())
+		require.Error(t, err) // the test server does not answer with a list
+
+		_, err = client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), limiter.acquired.Load())
+		assert.Equal(t, int32(2), limiter.released.Load())
+	})
+}
@@ -0,0 +1,125 @@
+package evatr
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures retries of transient failures. The zero value
+// disables retries.
+//
+// Only errors classified as CategoryTransient are retried: the eVatR statuses
+// documented as "temporarily not possible" (HTTP 500 and 503), 429/502/503/504
+// answers without an eVatR status and network errors. Answers with HTTP 400,
+// 403 and 404 are never retried.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one
+	MaxAttempts int
+
+	// Backoff before the first retry
+	InitialBackoff time.Duration
+
+	// Upper bound for a single backoff (0 means no limit). Retry-After
+	// answers asking for a longer wait are not retried; without a bound,
+	// the limit for them is maxRetryAfter.
+	MaxBackoff time.Duration
+
+	// Factor the backoff grows by with every retry (values below 1 mean 2)
+	Multiplier float64
+
+	// Fraction of the backoff that is randomized, between 0 and 1
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a conservative policy suitable for interactive use.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// maxRetryAfter limits the Retry-After wait of policies without MaxBackoff.
+const maxRetryAfter = time.Minute
+
+// WithRetryPolicy enables retries of transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// isRetryable returns whether err is worth another attempt.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return Classify(err) == CategoryTransient
+}
+
+// next returns how long to wait before the next attempt and whether there
+// should be one at all. It gives up if the wait would exceed the context
+// deadline or the server asks for a wait above the backoff limit.
+func (p RetryPolicy) next(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !isRetryable(ctx, err) {
+		return 0, false
+	}
+
+	wait := p.backoff(attempt)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.retryAfter > wait {
+		limit := p.MaxBackoff
+		if limit <= 0 {
+			limit = maxRetryAfter
+		}
+		if apiErr.retryAfter > limit {
+			return 0, false
+		}
+		wait = apiErr.retryAfter
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// backoff returns the randomized exponential backoff after the given attempt,
+// starting at 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * min(p.Jitter, 1) * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+// parseRetryAfter parses the Retry-After header, either delay seconds or an
+// HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
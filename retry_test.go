@@ -0,0 +1,174 @@
+package evatr_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = evatr.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+// failingServer answers with the given error for the first failures requests.
+func failingServer(t *testing.T, failures int32, statusCode int, status evatr.StatusCode) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) <= failures {
+			w.WriteHeader(statusCode)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: status})
+			return
+		}
+		json.NewEncoder(w).Encode(evatr.ValidationResponse{
+			RequestTimestamp: evatr.Time{Time: time.Now()},
+			Status:           evatr.StatusValid,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+// TestRetryPolicy tests retries of transient failures
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retries transient statuses", func(t *testing.T) {
+		server, calls := failingServer(t, 2, 503, evatr.StatusServiceUnavailable1)
+
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(testRetryPolicy))
+		result, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.True(t, result.IsValid())
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server, calls := failingServer(t, 5, 500, evatr.StatusProcessingError1)
+
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(testRetryPolicy))
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.Error(t, err)
+		assert.Equal(t, evatr.StatusProcessingError1, err.(*evatr.Error).Status)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			code   int
+			status evatr.StatusCode
+		}{
+			{400, evatr.StatusInvalidRequestedVATID},
+			{403, evatr.StatusNotAuthorizedDE},
+			{404, evatr.StatusVATIDNotAssigned},
+		} {
+			server, calls := failingServer(t, 1, tc.code, tc.status)
+
+			client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(testRetryPolicy))
+			_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+			require.Error(t, err)
+			assert.Equal(t, int32(1), calls.Load(), tc.status)
+		}
+	})
+
+	t.Run("does not retry a broken base URL", func(t *testing.T) {
+		policy := testRetryPolicy
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = 0
+
+		for _, baseURL := range []string{"ftp://127.0.0.1", "http://127.0.0.1:port"} {
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			client := evatr.NewClient(evatr.WithBaseURL(baseURL), evatr.WithRetryPolicy(policy))
+			_, err := client.ValidateVAT(ctx, "DE123456789", "ATU12345678")
+			cancel()
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, context.DeadlineExceeded, baseURL)
+			assert.False(t, evatr.IsRetryable(err), baseURL)
+		}
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		server, calls := failingServer(t, 1, 503, evatr.StatusServiceUnavailable2)
+
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL))
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("respects the context deadline", func(t *testing.T) {
+		server, calls := failingServer(t, 5, 503, evatr.StatusServiceUnavailable3)
+
+		policy := testRetryPolicy
+		policy.InitialBackoff = time.Minute
+		policy.MaxBackoff = 0
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(policy))
+		_, err := client.ValidateVAT(ctx, "DE123456789", "ATU12345678")
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		var calls atomic.Int32
+		var first time.Time
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) == 1 {
+				first = time.Now()
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(503)
+				json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable4})
+				return
+			}
+			assert.GreaterOrEqual(t, time.Since(first), time.Second)
+			json.NewEncoder(w).Encode(evatr.ValidationResponse{Status: evatr.StatusValid})
+		}))
+		defer server.Close()
+
+		policy := testRetryPolicy
+		policy.MaxBackoff = 2 * time.Second
+
+		client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(policy))
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("gives up on Retry-After above the backoff limit", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(503)
+			json.NewEncoder(w).Encode(evatr.ErrorResponse{Status: evatr.StatusServiceUnavailable4})
+		}))
+		defer server.Close()
+
+		for _, maxBackoff := range []time.Duration{0, testRetryPolicy.MaxBackoff, time.Hour} {
+			calls.Store(0)
+			policy := testRetryPolicy
+			policy.MaxBackoff = maxBackoff
+
+			client := evatr.NewClient(evatr.WithBaseURL(server.URL), evatr.WithRetryPolicy(policy))
+			_, err := client.ValidateVAT(context.Background(), "DE123456789", "ATU12345678")
+			require.Error(t, err)
+			assert.Equal(t, int32(1), calls.Load(), maxBackoff)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package evatr
+
+import (
+	"net/http"
+	"slices"
+)
+
+// StatusCode is an eVatR status code such as "evatr-0000".
+type StatusCode string
+
+// statusInfo holds the metadata of a status as documented by
+// /v1/info/statusmeldungen.
+type statusInfo struct {
+	httpCode int
+	category Category
+	field    string
+	german   string
+	english  string
+}
+
+const (
+	fieldRequesting = "anfragendeUstid"
+	fieldRequested  = "angefragteUstid"
+
+	unavailableGerman  = "Eine Bearbeitung Ihrer Anfrage ist zurzeit nicht möglich. Bitte versuchen Sie es später noch einmal."
+	unavailableEnglish = "Your request cannot be processed at the moment. Please try again later."
+)
+
+// statuses lists every status returned by /v1/info/statusmeldungen.
+var statuses = map[StatusCode]statusInfo{
+	StatusValid: {http.StatusOK, CategoryUnknown, "",
+		"Die angefragte Ust-IdNr. ist zum Anfragezeitpunkt gültig.",
+		"The requested VAT ID is valid at the time of the request."},
+	StatusNotYetValid: {http.StatusOK, CategoryUnknown, "",
+		"Die angefragte USt-IdNr. ist zum Anfragezeitpunkt nicht gültig. Sie ist erst gültig ab dem Datum im Feld gueltigAb.",
+		"The requested VAT ID is not valid at the time of the request. It is only valid from the date in the field gueltigAb."},
+	StatusNoLongerValid: {http.StatusOK, CategoryUnknown, "",
+		"Die angefragte Ust-IdNr. ist zum Anfragezeitpunkt nicht gültig. Sie war gültig im Zeitraum, der durch die Werte in den Feldern gueltigAb und gueltigBis beschrieben ist.",
+		"The requested VAT ID is not valid at the time of the request. It was valid in the period described by the fields gueltigAb and gueltigBis."},
+	StatusValidWithSpecialCase: {http.StatusOK, CategoryUnknown, "",
+		"Die angefragte Ust-IdNr. ist zum Anfragezeitpunkt gültig. Für die qualifizierte Bestätigungsanfrage liegt einer Besonderheit vor. Für Rückfragen wenden Sie sich an das BZSt.",
+		"The requested VAT ID is valid at the time of the request. The qualified confirmation request is a special case. Please contact the BZSt for details."},
+
+	StatusMissingRequiredField: {http.StatusBadRequest, CategoryInvalidInput, "",
+		"Mindestens eins der Pflichtfelder ist nicht besetzt.",
+		"At least one of the required fields is empty."},
+	StatusInvalidRequestingVATID: {http.StatusBadRequest, CategoryConfiguration, fieldRequesting,
+		"Die anfragende DE Ust-IdNr. ist syntaktisch falsch. Sie passt nicht in das deutsche Erzeugungsschema.",
+		"The requesting German VAT ID is syntactically wrong. It does not match the German format."},
+	StatusInvalidRequestedVATID: {http.StatusBadRequest, CategoryInvalidInput, fieldRequested,
+		"Die angegebene angefragte Ust-IdNr. ist syntaktisch falsch.",
+		"The requested VAT ID is syntactically wrong."},
+	StatusMaxQualifiedRequestsReached: {http.StatusBadRequest, CategorySessionLimit, "",
+		"Die maximale Anzahl von qualifizierten Bestätigungsabfragen für diese Session wurde erreicht. Bitte starten Sie erneut mit einer einfachen Bestätigungsabfrage.",
+		"The maximum number of qualified confirmation requests for this session has been reached. Please start again with a simple confirmation request."},
+	StatusInvalidVATIDFormat: {http.StatusBadRequest, CategoryInvalidInput, fieldRequested,
+		"Die angefrage USt-IdNr. ist syntaktisch falsch. Sie passt nicht in das Erzeugungsschema.",
+		"The requested VAT ID is syntactically wrong. It does not match the format of the member state."},
+	StatusInvalidCountryCode: {http.StatusBadRequest, CategoryInvalidInput, fieldRequested,
+		"Das angegebene Länderkennzeichen der angefragten USt-IdNr. ist nicht gültig.",
+		"The country code of the requested VAT ID is not valid."},
+
+	StatusNotAuthorizedDE: {http.StatusForbidden, CategoryConfiguration, fieldRequesting,
+		"Die anfragende DE USt-IdNr. ist nicht berechtigt eine DE Ust-IdNr. anzufragen.",
+		"The requesting German VAT ID is not authorized to request a German VAT ID."},
+	StatusInvalidCall: {http.StatusForbidden, CategoryInvalidInput, "",
+		"Fehlerhafter Aufruf.",
+		"Invalid call."},
+
+	StatusVATIDNotAssigned: {http.StatusNotFound, CategoryNotAssigned, fieldRequested,
+		"Die angefragte USt-IdNr. ist zum Anfragezeitpunkt nicht vergeben.",
+		"The requested VAT ID is not assigned at the time of the request."},
+	StatusRequestingVATIDNotValid: {http.StatusNotFound, CategoryConfiguration, fieldRequesting,
+		"Die angegebene eigene DE Ust-IdNr. ist zum Anfragezeitpunkt nicht gültig.",
+		"The given own German VAT ID is not valid at the time of the request."},
+
+	StatusProcessingError1:    {http.StatusInternalServerError, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusProcessingError2:    {http.StatusInternalServerError, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusProcessingError3:    {http.StatusInternalServerError, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusServiceUnavailable1: {http.StatusServiceUnavailable, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusServiceUnavailable2: {http.StatusServiceUnavailable, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusServiceUnavailable3: {http.StatusServiceUnavailable, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusServiceUnavailable4: {http.StatusServiceUnavailable, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+	StatusServiceUnavailable5: {http.StatusServiceUnavailable, CategoryTransient, "", unavailableGerman, unavailableEnglish},
+}
+
+// StatusCodes returns all known status codes.
+func StatusCodes() []StatusCode {
+	codes := make([]StatusCode, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+func (s StatusCode) String() string {
+	return string(s)
+}
+
+// IsKnown returns whether the status is documented.
+func (s StatusCode) IsKnown() bool {
+	_, ok := statuses[s]
+	return ok
+}
+
+// HTTPCode returns the HTTP status code the API answers the status with,
+// 0 if the status is unknown.
+func (s StatusCode) HTTPCode() int {
+	return statuses[s].httpCode
+}
+
+// IsResult returns whether the status is a result of a validation
+// (HTTP 200) rather than an error.
+func (s StatusCode) IsResult() bool {
+	return s.HTTPCode() == http.StatusOK
+}
+
+// Category returns the category of an error status. Results and unknown
+// statuses are CategoryUnknown.
+func (s StatusCode) Category() Category {
+	return statuses[s].category
+}
+
+// Field returns the request field the status refers to, if any.
+func (s StatusCode) Field() string {
+	return statuses[s].field
+}
+
+// GermanDescription returns the message of the status as documented by the BZSt.
+func (s StatusCode) GermanDescription() string {
+	return statuses[s].german
+}
+
+// EnglishDescription returns an English translation of the message.
+func (s StatusCode) EnglishDescription() string {
+	return statuses[s].english
+}
+
+// IsValid returns whether the VAT ID is valid, possibly with a special case.
+func (s StatusCode) IsValid() bool {
+	return s == StatusValid || s == StatusValidWithSpecialCase
+}
+
+// IsTransient returns whether the status reports a temporary failure.
+func (s StatusCode) IsTransient() bool {
+	return s.Category() == CategoryTransient
+}
+
+// IsNotYetValid returns whether the VAT ID is only valid from ValidFrom on.
+func (s StatusCode) IsNotYetValid() bool {
+	return s == StatusNotYetValid
+}
+
+// IsNoLongerValid returns whether the VAT ID was valid from ValidFrom until ValidUntil.
+func (s StatusCode) IsNoLongerValid() bool {
+	return s == StatusNoLongerValid
+}
+
+// IsSpecialCase returns whether the VAT ID is valid but the qualified
+// confirmation needs clarification with the BZSt.
+func (s StatusCode) IsSpecialCase() bool {
+	return s == StatusValidWithSpecialCase
+}
@@ -0,0 +1,54 @@
+package evatr_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStatusCodes tests the metadata of every known status
+func TestStatusCodes(t *testing.T) {
+	codes := evatr.StatusCodes()
+	assert.Len(t, codes, 22)
+	assert.IsIncreasing(t, codes)
+
+	for _, code := range codes {
+		assert.True(t, code.IsKnown(), code)
+		assert.NotZero(t, code.HTTPCode(), code)
+		assert.NotEmpty(t, code.GermanDescription(), code)
+		assert.NotEmpty(t, code.EnglishDescription(), code)
+
+		if code.IsResult() {
+			assert.Equal(t, evatr.CategoryUnknown, code.Category(), code)
+		} else {
+			assert.NotEqual(t, evatr.CategoryUnknown, code.Category(), code)
+		}
+	}
+}
+
+// TestStatusCodeMethods tests the predicates and lookups of StatusCode
+func TestStatusCodeMethods(t *testing.T) {
+	assert.True(t, evatr.StatusValid.IsValid())
+	assert.True(t, evatr.StatusValidWithSpecialCase.IsValid())
+	assert.True(t, evatr.StatusValidWithSpecialCase.IsSpecialCase())
+	assert.False(t, evatr.StatusNoLongerValid.IsValid())
+	assert.True(t, evatr.StatusNoLongerValid.IsNoLongerValid())
+	assert.True(t, evatr.StatusNotYetValid.IsNotYetValid())
+	assert.True(t, evatr.StatusServiceUnavailable2.IsTransient())
+	assert.False(t, evatr.StatusVATIDNotAssigned.IsTransient())
+
+	assert.Equal(t, http.StatusForbidden, evatr.StatusNotAuthorizedDE.HTTPCode())
+	assert.Equal(t, evatr.CategoryConfiguration, evatr.StatusNotAuthorizedDE.Category())
+	assert.Equal(t, "anfragendeUstid", evatr.StatusNotAuthorizedDE.Field())
+	assert.Equal(t, "angefragteUstid", evatr.StatusVATIDNotAssigned.Field())
+	assert.Equal(t, "Fehlerhafter Aufruf.", evatr.StatusInvalidCall.GermanDescription())
+	assert.Equal(t, "Invalid call.", evatr.StatusInvalidCall.EnglishDescription())
+
+	unknown := evatr.StatusCode("evatr-9999")
+	assert.False(t, unknown.IsKnown())
+	assert.Zero(t, unknown.HTTPCode())
+	assert.False(t, unknown.IsResult())
+	assert.Empty(t, unknown.GermanDescription())
+}
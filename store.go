@@ -0,0 +1,233 @@
+package evatr
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// ErrInvalidCursor is returned by stores for a cursor they did not issue.
+var ErrInvalidCursor = errors.New("evatr: invalid cursor")
+
+// ValidationRecord is a validation result kept in a Store.
+type ValidationRecord struct {
+	// Assigned by the store on insert
+	Seq uint64 `json:"seq"`
+
+	// Request with normalized VAT IDs
+	Request ValidationRequest `json:"request"`
+
+	// Response, or the API error answer
+	Response *ValidationResponse `json:"response,omitempty"`
+	Err      *Error              `json:"error,omitempty"`
+
+	// Time the result was returned to the caller
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Whether the result was served from the cache
+	CacheHit bool `json:"cache_hit,omitempty"`
+}
+
+// Status returns the eVatR status of the response or error.
+func (r ValidationRecord) Status() StatusCode {
+	switch {
+	case r.Response != nil:
+		return r.Response.Status
+	case r.Err != nil:
+		return r.Err.Status
+	default:
+		return ""
+	}
+}
+
+// RecordQuery selects records of a Store. Zero fields match all records.
+type RecordQuery struct {
+	// Requested VAT ID, normalized before the lookup
+	VATID string
+
+	// Range of CheckedAt, From inclusive and Until exclusive
+	From  time.Time
+	Until time.Time
+
+	// Newest records first instead of oldest first
+	Descending bool
+
+	// Maximum number of records (defaults to DefaultPageSize)
+	Limit int
+
+	// NextCursor of the previous page
+	Cursor string
+}
+
+// DefaultPageSize is the number of records per page if a query has no Limit.
+const DefaultPageSize = 100
+
+// RecordPage is a page of query results.
+type RecordPage struct {
+	Records []ValidationRecord
+
+	// Cursor of the next page, empty on the last page
+	NextCursor string
+}
+
+// Store keeps validation results, e.g. to answer what the API said about a
+// customer months ago. Implementations must be safe for concurrent use. See
+// package boltstore for a store in a file.
+type Store interface {
+	// Insert stores a record and returns its sequence number. A zero
+	// CheckedAt is set to the current time.
+	Insert(ctx context.Context, rec ValidationRecord) (uint64, error)
+
+	// Query returns the records matching q, ordered by CheckedAt.
+	Query(ctx context.Context, q RecordQuery) (RecordPage, error)
+}
+
+// StoreOptions configures how the client records results.
+type StoreOptions struct {
+	// Called when a result could not be stored; the result is returned anyway
+	OnError func(ValidationRecord, error)
+
+	// Do not record results served from the cache
+	SkipCacheHits bool
+}
+
+// WithStore records the result of every ValidateVAT* call that reached the
+// API or the cache: responses and API error answers. Input errors and
+// failed requests are not recorded.
+func WithStore(store Store, opts StoreOptions) Option {
+	return func(c *Client) {
+		c.store = store
+		c.storeOptions = opts
+	}
+}
+
+// storeResult records the result of a validation.
+func (c *Client) storeResult(ctx context.Context, req *ValidationRequest, resp *ValidationResponse, cacheHit bool, err error) {
+	if c.store == nil || (cacheHit && c.storeOptions.SkipCacheHits) {
+		return
+	}
+
+	rec := ValidationRecord{
+		Request:   *req,
+		Response:  resp,
+		CheckedAt: time.Now(),
+		CacheHit:  cacheHit,
+	}
+	if err != nil && !errors.As(err, &rec.Err) {
+		return
+	}
+	if rec.Response == nil && rec.Err == nil {
+		return
+	}
+
+	// Store the result even if the caller gave up waiting for it.
+	if _, err := c.store.Insert(context.WithoutCancel(ctx), rec); err != nil && c.storeOptions.OnError != nil {
+		c.storeOptions.OnError(rec, err)
+	}
+}
+
+// MemoryStore is a Store in memory, e.g. for tests.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records []ValidationRecord
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Insert implements Store.
+func (s *MemoryStore) Insert(_ context.Context, rec ValidationRecord) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec.Seq = uint64(len(s.records)) + 1
+	rec.Request.RequestedVATID = vatid.Normalize(rec.Request.RequestedVATID)
+	if rec.CheckedAt.IsZero() {
+		rec.CheckedAt = time.Now()
+	}
+
+	// Keep the records ordered by CheckedAt, then Seq.
+	i, _ := slices.BinarySearchFunc(s.records, rec, compareRecords)
+	s.records = slices.Insert(s.records, i, rec)
+	return rec.Seq, nil
+}
+
+// Query implements Store.
+func (s *MemoryStore) Query(_ context.Context, q RecordQuery) (RecordPage, error) {
+	after, err := parseMemoryCursor(q.Cursor)
+	if err != nil {
+		return RecordPage{}, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	vatID := vatid.Normalize(q.VATID)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var page RecordPage
+	for i := range s.records {
+		rec := s.records[i]
+		if q.Descending {
+			rec = s.records[len(s.records)-1-i]
+		}
+
+		if after != nil {
+			c := compareRecords(rec, *after)
+			if (!q.Descending && c <= 0) || (q.Descending && c >= 0) {
+				continue
+			}
+		}
+		if vatID != "" && rec.Request.RequestedVATID != vatID {
+			continue
+		}
+		if !q.From.IsZero() && rec.CheckedAt.Before(q.From) {
+			continue
+		}
+		if !q.Until.IsZero() && !rec.CheckedAt.Before(q.Until) {
+			continue
+		}
+
+		if len(page.Records) == limit {
+			last := page.Records[limit-1]
+			page.NextCursor = fmt.Sprintf("%d.%d", last.CheckedAt.UnixNano(), last.Seq)
+			break
+		}
+		page.Records = append(page.Records, rec)
+	}
+	return page, nil
+}
+
+func compareRecords(a, b ValidationRecord) int {
+	if c := a.CheckedAt.Compare(b.CheckedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Seq, b.Seq)
+}
+
+// parseMemoryCursor returns the position of a cursor issued by Query.
+func parseMemoryCursor(cursor string) (*ValidationRecord, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	nanos, seq, ok := strings.Cut(cursor, ".")
+	n, err1 := strconv.ParseInt(nanos, 10, 64)
+	s, err2 := strconv.ParseUint(seq, 10, 64)
+	if !ok || err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+	}
+	return &ValidationRecord{CheckedAt: time.Unix(0, n), Seq: s}, nil
+}
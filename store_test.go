@@ -0,0 +1,105 @@
+package evatr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/hostwithquantum/go-evatr/storetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryStore tests the in-memory store
+func TestMemoryStore(t *testing.T) {
+	storetest.TestStore(t, func(*testing.T) evatr.Store {
+		return evatr.NewMemoryStore()
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Insert(context.Context, evatr.ValidationRecord) (uint64, error) {
+	return 0, errors.New("disk full")
+}
+
+func (failingStore) Query(context.Context, evatr.RecordQuery) (evatr.RecordPage, error) {
+	return evatr.RecordPage{}, nil
+}
+
+// TestWithStore tests the client records every validation result
+func TestWithStore(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+	srv.SetResponse("ATU99999999", evatrtest.Response{Status: evatr.StatusVATIDNotAssigned})
+
+	store := evatr.NewMemoryStore()
+	client := srv.Client(
+		evatr.WithStore(store, evatr.StoreOptions{}),
+		evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy),
+	)
+
+	ctx := t.Context()
+	_, err := client.ValidateVATQualified(ctx, "DE123456789", "atu 12345678", "Musterhaus GmbH", "Wien", "", "")
+	require.NoError(t, err)
+	_, err = client.ValidateVATQualified(ctx, "DE123456789", "ATU12345678", "Musterhaus GmbH", "Wien", "", "")
+	require.NoError(t, err)
+	_, err = client.ValidateVAT(ctx, "DE123456789", "ATU99999999")
+	require.Error(t, err)
+	_, err = client.ValidateVAT(ctx, "DE123456789", "")
+	require.ErrorIs(t, err, evatr.ErrMissingRequestedVATID)
+
+	page, err := store.Query(ctx, evatr.RecordQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 3, "input errors are not recorded")
+
+	first := page.Records[0]
+	assert.Equal(t, "ATU12345678", first.Request.RequestedVATID)
+	assert.Equal(t, "Musterhaus GmbH", first.Request.CompanyName)
+	assert.Equal(t, evatr.StatusValid, first.Status())
+	assert.Equal(t, evatr.VerificationMatch, first.Response.CompanyNameResult)
+	assert.False(t, first.CacheHit)
+	assert.False(t, first.CheckedAt.IsZero())
+
+	assert.True(t, page.Records[1].CacheHit)
+
+	last := page.Records[2]
+	assert.Nil(t, last.Response)
+	assert.Equal(t, evatr.StatusVATIDNotAssigned, last.Status())
+
+	// Results are returned even if they cannot be stored.
+	var stored []evatr.ValidationRecord
+	client = srv.Client(evatr.WithStore(failingStore{}, evatr.StoreOptions{
+		OnError: func(rec evatr.ValidationRecord, err error) {
+			stored = append(stored, rec)
+			assert.EqualError(t, err, "disk full")
+		},
+	}))
+	resp, err := client.ValidateVAT(ctx, "DE123456789", "ATU12345678")
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, resp, stored[0].Response)
+}
+
+// TestWithStoreSkipCacheHits tests cached results can be left out
+func TestWithStoreSkipCacheHits(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	store := evatr.NewMemoryStore()
+	client := srv.Client(
+		evatr.WithStore(store, evatr.StoreOptions{SkipCacheHits: true}),
+		evatr.WithCache(evatr.NewLRUCache(10), evatr.DefaultCachePolicy),
+	)
+
+	for range 3 {
+		_, err := client.ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+		require.NoError(t, err)
+	}
+
+	page, err := store.Query(t.Context(), evatr.RecordQuery{VATID: "ATU12345678"})
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 1)
+}
@@ -0,0 +1,142 @@
+// Package storetest tests implementations of evatr.Store:
+//
+//	func TestStore(t *testing.T) {
+//		storetest.TestStore(t, func(t *testing.T) evatr.Store {
+//			return newStore(t)
+//		})
+//	}
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore tests an evatr.Store implementation: inserts, lookups by VAT ID,
+// time ranges, ordering and pagination. newStore must return an empty store.
+func TestStore(t *testing.T, newStore func(t *testing.T) evatr.Store) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Records of two VAT IDs, inserted out of order; one per day.
+	fill := func(t *testing.T) evatr.Store {
+		t.Helper()
+		store := newStore(t)
+		for _, day := range []int{3, 0, 4, 1, 2, 5} {
+			vatID := "ATU12345678"
+			if day%2 == 1 {
+				vatID = "fr 12345678901"
+			}
+			rec := evatr.ValidationRecord{
+				Request:   evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: vatID},
+				Response:  &evatr.ValidationResponse{ID: fmt.Sprintf("day-%d", day), Status: evatr.StatusValid},
+				CheckedAt: base.AddDate(0, 0, day),
+			}
+			if day == 5 {
+				rec.Response = nil
+				rec.Err = &evatr.Error{StatusCode: 404, Status: evatr.StatusVATIDNotAssigned, Message: "not assigned"}
+			}
+			seq, err := store.Insert(ctx, rec)
+			require.NoError(t, err)
+			require.NotZero(t, seq)
+		}
+		return store
+	}
+
+	days := func(records []evatr.ValidationRecord) []int {
+		var days []int
+		for _, rec := range records {
+			days = append(days, int(rec.CheckedAt.Sub(base).Hours()/24))
+		}
+		return days
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		store := fill(t)
+		tests := []struct {
+			name  string
+			query evatr.RecordQuery
+			days  []int
+		}{
+			{"all", evatr.RecordQuery{}, []int{0, 1, 2, 3, 4, 5}},
+			{"descending", evatr.RecordQuery{Descending: true}, []int{5, 4, 3, 2, 1, 0}},
+			{"VAT ID", evatr.RecordQuery{VATID: "ATU12345678"}, []int{0, 2, 4}},
+			{"normalized VAT ID", evatr.RecordQuery{VATID: "FR 123 456 789 01"}, []int{1, 3, 5}},
+			{"VAT ID descending", evatr.RecordQuery{VATID: "FR12345678901", Descending: true}, []int{5, 3, 1}},
+			{"unknown VAT ID", evatr.RecordQuery{VATID: "ATU1234567"}, nil},
+			{"from", evatr.RecordQuery{From: base.AddDate(0, 0, 4)}, []int{4, 5}},
+			{"until", evatr.RecordQuery{Until: base.AddDate(0, 0, 2)}, []int{0, 1}},
+			{"range", evatr.RecordQuery{From: base.AddDate(0, 0, 1), Until: base.AddDate(0, 0, 4)}, []int{1, 2, 3}},
+			{"range descending", evatr.RecordQuery{From: base.AddDate(0, 0, 1), Until: base.AddDate(0, 0, 4), Descending: true}, []int{3, 2, 1}},
+			{"VAT ID and range", evatr.RecordQuery{VATID: "ATU12345678", From: base.AddDate(0, 0, 1), Until: base.AddDate(0, 0, 5)}, []int{2, 4}},
+			{"limit", evatr.RecordQuery{Limit: 2}, []int{0, 1}},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				page, err := store.Query(ctx, tc.query)
+				require.NoError(t, err)
+				assert.Equal(t, tc.days, days(page.Records))
+			})
+		}
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		store := fill(t)
+		page, err := store.Query(ctx, evatr.RecordQuery{VATID: "FR12345678901", Descending: true, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 1)
+
+		rec := page.Records[0]
+		assert.NotZero(t, rec.Seq)
+		assert.Equal(t, "FR12345678901", rec.Request.RequestedVATID, "normalized VAT ID")
+		assert.True(t, rec.CheckedAt.Equal(base.AddDate(0, 0, 5)), "CheckedAt = %v", rec.CheckedAt)
+		assert.Nil(t, rec.Response)
+		require.NotNil(t, rec.Err)
+		assert.Equal(t, evatr.StatusVATIDNotAssigned, rec.Status())
+		assert.Equal(t, 404, rec.Err.StatusCode)
+	})
+
+	for _, descending := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Pagination descending=%v", descending), func(t *testing.T) {
+			store := fill(t)
+			query := evatr.RecordQuery{Limit: 4, Descending: descending}
+
+			var got []int
+			for pages := 0; ; pages++ {
+				require.LessOrEqual(t, pages, 3, "pagination does not end")
+				page, err := store.Query(ctx, query)
+				require.NoError(t, err)
+				got = append(got, days(page.Records)...)
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+
+			want := []int{0, 1, 2, 3, 4, 5}
+			if descending {
+				slices.Reverse(want)
+			}
+			assert.Equal(t, want, got)
+
+			// An exactly full last page has no cursor.
+			page, err := store.Query(ctx, evatr.RecordQuery{Limit: 6, Descending: descending})
+			require.NoError(t, err)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		store := fill(t)
+		_, err := store.Query(ctx, evatr.RecordQuery{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, evatr.ErrInvalidCursor)
+	})
+}
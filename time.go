@@ -0,0 +1,141 @@
+package evatr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTime is returned when decoding a timestamp or date the API sent
+// in an unexpected format.
+var ErrInvalidTime = errors.New("evatr: invalid time")
+
+// Time is a timestamp or date of a validation response, normalized to
+// Europe/Berlin. The API sends RFC 3339 timestamps and, for validity dates,
+// plain YYYY-MM-DD dates. All encodings keep DateOnly; the ones promoted from
+// time.Time would drop it.
+type Time struct {
+	time.Time
+
+	// The API sent a date without time of day
+	DateOnly bool
+}
+
+// timeLayouts are the accepted formats without a date-only value.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+// ParseTime parses a timestamp or date of the API. Values without time zone
+// are interpreted in Europe/Berlin.
+func ParseTime(s string) (Time, error) {
+	if s == "" {
+		return Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, s, berlin); err == nil {
+		return Time{Time: t, DateOnly: true}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, berlin); err == nil {
+			return Time{Time: t.In(berlin)}, nil
+		}
+	}
+
+	return Time{}, fmt.Errorf("%w %q: want an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidTime, s)
+}
+
+// String formats the time like the API, as date for date-only values.
+func (t Time) String() string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.DateOnly:
+		return t.Format(time.DateOnly)
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Null and empty strings decode
+// to the zero value.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w %s: want a string", ErrInvalidTime, data)
+	}
+
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, in the format of String.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTime.
+func (t *Time) UnmarshalText(data []byte) error {
+	parsed, err := ParseTime(string(data))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler: a DateOnly flag byte
+// followed by the binary encoding of time.Time.
+func (t Time) MarshalBinary() ([]byte, error) {
+	data, err := t.Time.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var flag byte
+	if t.DateOnly {
+		flag = 1
+	}
+	return append([]byte{flag}, data...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (t *Time) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] > 1 {
+		return fmt.Errorf("%w: invalid binary encoding", ErrInvalidTime)
+	}
+	var parsed time.Time
+	if err := parsed.UnmarshalBinary(data[1:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTime, err)
+	}
+	if !parsed.IsZero() {
+		parsed = parsed.In(berlin)
+	}
+	*t = Time{Time: parsed, DateOnly: data[0] == 1}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (t Time) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder.
+func (t *Time) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
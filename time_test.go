@@ -0,0 +1,127 @@
+package evatr_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/hostwithquantum/go-evatr/evatrtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTime tests the accepted formats
+func TestParseTime(t *testing.T) {
+	t.Run("timestamp", func(t *testing.T) {
+		parsed, err := evatr.ParseTime("2025-07-15T08:30:00Z")
+		require.NoError(t, err)
+		assert.False(t, parsed.DateOnly)
+		assert.Equal(t, "Europe/Berlin", parsed.Location().String())
+		assert.Equal(t, 10, parsed.Hour())
+		assert.True(t, parsed.Equal(time.Date(2025, 7, 15, 8, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("timestamp without zone", func(t *testing.T) {
+		parsed, err := evatr.ParseTime("2025-01-15T08:30:00.123")
+		require.NoError(t, err)
+		assert.True(t, parsed.Equal(time.Date(2025, 1, 15, 7, 30, 0, 123e6, time.UTC)))
+	})
+
+	t.Run("date", func(t *testing.T) {
+		parsed, err := evatr.ParseTime("2024-12-31")
+		require.NoError(t, err)
+		assert.True(t, parsed.DateOnly)
+		assert.Equal(t, "2024-12-31", parsed.String())
+		assert.Equal(t, 0, parsed.Hour())
+		assert.Equal(t, "Europe/Berlin", parsed.Location().String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		parsed, err := evatr.ParseTime("")
+		require.NoError(t, err)
+		assert.True(t, parsed.IsZero())
+		assert.Empty(t, parsed.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"31.12.2024", "2024-13-01", "tomorrow"} {
+			_, err := evatr.ParseTime(s)
+			assert.ErrorIs(t, err, evatr.ErrInvalidTime, s)
+			assert.ErrorContains(t, err, s)
+		}
+	})
+}
+
+// TestTimeJSON tests decoding and encoding of responses
+func TestTimeJSON(t *testing.T) {
+	var resp evatr.ValidationResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"anfrageZeitpunkt": "2025-07-15T10:30:00+02:00",
+		"gueltigAb": "2020-01-01",
+		"gueltigBis": null,
+		"status": "evatr-2006"
+	}`), &resp))
+
+	assert.Equal(t, 2025, resp.RequestTimestamp.Year())
+	assert.True(t, resp.ValidFrom.DateOnly)
+	assert.True(t, resp.ValidUntil.IsZero())
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"anfrageZeitpunkt":"2025-07-15T10:30:00+02:00"`)
+	assert.Contains(t, string(data), `"gueltigAb":"2020-01-01"`)
+	assert.NotContains(t, string(data), "gueltigBis")
+
+	err = json.Unmarshal([]byte(`{"anfrageZeitpunkt": 1752568200}`), &resp)
+	assert.ErrorIs(t, err, evatr.ErrInvalidTime)
+}
+
+// TestTimeEncodings tests text, binary and gob encodings keep DateOnly
+func TestTimeEncodings(t *testing.T) {
+	date, err := evatr.ParseTime("2020-01-01")
+	require.NoError(t, err)
+	timestamp, err := evatr.ParseTime("2025-07-15T10:30:00+02:00")
+	require.NoError(t, err)
+
+	for _, value := range []evatr.Time{date, timestamp, {}} {
+		text, err := value.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, value.String(), string(text))
+		var fromText evatr.Time
+		require.NoError(t, fromText.UnmarshalText(text))
+		assert.Equal(t, value.DateOnly, fromText.DateOnly, value)
+		assert.True(t, value.Equal(fromText.Time), value)
+
+		data, err := value.MarshalBinary()
+		require.NoError(t, err)
+		var fromBinary evatr.Time
+		require.NoError(t, fromBinary.UnmarshalBinary(data))
+		assert.Equal(t, value.DateOnly, fromBinary.DateOnly, value)
+		assert.Equal(t, value.String(), fromBinary.String())
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(value))
+		var fromGob evatr.Time
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&fromGob))
+		assert.Equal(t, value.DateOnly, fromGob.DateOnly, value)
+		assert.Equal(t, value.String(), fromGob.String())
+	}
+
+	var decoded evatr.Time
+	assert.ErrorIs(t, decoded.UnmarshalBinary(nil), evatr.ErrInvalidTime)
+	assert.ErrorIs(t, decoded.UnmarshalText([]byte("01.01.2020")), evatr.ErrInvalidTime)
+}
+
+// TestTimeDecodingError tests that the client rejects unexpected dates
+func TestTimeDecodingError(t *testing.T) {
+	srv := evatrtest.NewServer()
+	defer srv.Close()
+
+	srv.SetResponse("ATU12345678", evatrtest.Response{Status: evatr.StatusNoLongerValid, ValidFrom: "01.01.2020"})
+	_, err := srv.Client().ValidateVAT(t.Context(), "DE123456789", "ATU12345678")
+	require.ErrorIs(t, err, evatr.ErrInvalidTime)
+	assert.ErrorContains(t, err, `"01.01.2020"`)
+}
@@ -0,0 +1,136 @@
+package evatr
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Language is a language tag such as "en" or "en-GB". Only the primary
+// subtag is used to look up translations.
+type Language string
+
+// Built-in languages of status messages.
+const (
+	LanguageGerman  Language = "de"
+	LanguageEnglish Language = "en"
+)
+
+// base returns the lower-case primary subtag of the language.
+func (l Language) base() Language {
+	tag := strings.ToLower(string(l))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return Language(tag)
+}
+
+// Translate returns the built-in message of the status in the given
+// language, falling back to German. It returns false for unknown statuses.
+func (s StatusCode) Translate(lang Language) (string, bool) {
+	info, ok := statuses[s]
+	if !ok {
+		return "", false
+	}
+	if lang.base() == LanguageEnglish {
+		return info.english, true
+	}
+	return info.german, true
+}
+
+// Translator adds custom messages to the built-in ones, e.g. French or
+// reworded English texts. The zero value only knows the built-in messages.
+// It is safe for concurrent use.
+type Translator struct {
+	mu       sync.RWMutex
+	messages map[Language]map[StatusCode]string
+}
+
+// Add adds or replaces messages of a language.
+func (t *Translator) Add(lang Language, messages map[StatusCode]string) {
+	lang = lang.base()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.messages == nil {
+		t.messages = make(map[Language]map[StatusCode]string)
+	}
+	merged := maps.Clone(t.messages[lang])
+	if merged == nil {
+		merged = make(map[StatusCode]string, len(messages))
+	}
+	maps.Copy(merged, messages)
+	t.messages[lang] = merged
+}
+
+// Languages returns the built-in languages and those with custom messages.
+func (t *Translator) Languages() []Language {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	langs := []Language{LanguageGerman, LanguageEnglish}
+	for lang := range t.messages {
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Translate returns the custom message of the status in the given language,
+// falling back to StatusCode.Translate.
+func (t *Translator) Translate(s StatusCode, lang Language) (string, bool) {
+	t.mu.RLock()
+	msg, ok := t.messages[lang.base()][s]
+	t.mu.RUnlock()
+	if ok {
+		return msg, true
+	}
+	return s.Translate(lang)
+}
+
+// Message returns the message of an error in the given language, using the
+// custom messages for API errors wrapped in err. Other errors and unknown
+// statuses fall back to the text of the error.
+func (t *Translator) Message(err error, lang Language) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	if msg, ok := t.Translate(apiErr.Status, lang); ok {
+		return msg
+	}
+	return apiErr.Message
+}
+
+// LocalizedMessage returns the message of the error in the given language.
+// For unknown statuses it falls back to the message sent by the API.
+func (e *Error) LocalizedMessage(lang Language) string {
+	if msg, ok := e.Status.Translate(lang); ok {
+		return msg
+	}
+	return e.Message
+}
+
+// LocalizedMessage returns the meaning of the response status in the given
+// language, or an empty string for unknown statuses.
+func (v *ValidationResponse) LocalizedMessage(lang Language) string {
+	msg, _ := v.Status.Translate(lang)
+	return msg
+}
+
+// LocalizedMessage returns the message in the given language. For unknown
+// statuses it falls back to the German message of the API.
+func (m StatusMessage) LocalizedMessage(lang Language) string {
+	if msg, ok := m.Status.Translate(lang); ok {
+		return msg
+	}
+	return m.Message
+}
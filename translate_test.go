@@ -0,0 +1,87 @@
+package evatr_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTranslate tests status messages in other languages
+func TestTranslate(t *testing.T) {
+	for _, code := range evatr.StatusCodes() {
+		english, ok := code.Translate(evatr.LanguageEnglish)
+		assert.True(t, ok, code)
+		assert.Equal(t, code.EnglishDescription(), english, code)
+
+		german, _ := code.Translate(evatr.LanguageGerman)
+		assert.Equal(t, code.GermanDescription(), german, code)
+	}
+
+	msg, ok := evatr.StatusInvalidCall.Translate("en-GB")
+	assert.True(t, ok)
+	assert.Equal(t, "Invalid call.", msg)
+
+	// Languages without translations fall back to German.
+	msg, ok = evatr.StatusInvalidCall.Translate("pl")
+	assert.True(t, ok)
+	assert.Equal(t, "Fehlerhafter Aufruf.", msg)
+
+	_, ok = evatr.StatusCode("evatr-9999").Translate(evatr.LanguageEnglish)
+	assert.False(t, ok)
+}
+
+// TestLocalizedMessage tests translations of errors and responses
+func TestLocalizedMessage(t *testing.T) {
+	apiErr := evatr.NewNotFoundError(evatr.StatusVATIDNotAssigned, "Die angefragte USt-IdNr. ist zum Anfragezeitpunkt nicht vergeben.")
+	assert.Equal(t, "The requested VAT ID is not assigned at the time of the request.", apiErr.LocalizedMessage(evatr.LanguageEnglish))
+
+	unknown := evatr.NewBadRequestError("evatr-9999", "Unbekannter Fehler.")
+	assert.Equal(t, "Unbekannter Fehler.", unknown.LocalizedMessage(evatr.LanguageEnglish))
+
+	resp := &evatr.ValidationResponse{Status: evatr.StatusNoLongerValid}
+	assert.Contains(t, resp.LocalizedMessage(evatr.LanguageEnglish), "gueltigAb and gueltigBis")
+	assert.Empty(t, (&evatr.ValidationResponse{Status: "evatr-9999"}).LocalizedMessage(evatr.LanguageEnglish))
+
+	msg := evatr.StatusMessage{Status: "evatr-9999", Message: "Neu."}
+	assert.Equal(t, "Neu.", msg.LocalizedMessage(evatr.LanguageEnglish))
+}
+
+// TestTranslator tests adding a language
+func TestTranslator(t *testing.T) {
+	var tr evatr.Translator
+	assert.Equal(t, []evatr.Language{"de", "en"}, tr.Languages())
+
+	tr.Add("fr", map[evatr.StatusCode]string{
+		evatr.StatusValid: "Le numéro de TVA demandé est valide au moment de la demande.",
+	})
+	tr.Add("en-GB", map[evatr.StatusCode]string{
+		evatr.StatusInvalidCall: "Invalid request.",
+	})
+	assert.Equal(t, []evatr.Language{"de", "en", "fr"}, tr.Languages())
+
+	msg, _ := tr.Translate(evatr.StatusValid, "fr-BE")
+	assert.Equal(t, "Le numéro de TVA demandé est valide au moment de la demande.", msg)
+
+	msg, _ = tr.Translate(evatr.StatusInvalidCall, "fr")
+	assert.Equal(t, "Fehlerhafter Aufruf.", msg)
+
+	msg, _ = tr.Translate(evatr.StatusInvalidCall, evatr.LanguageEnglish)
+	assert.Equal(t, "Invalid request.", msg)
+
+	// The built-in messages are not affected.
+	msg, _ = evatr.StatusInvalidCall.Translate(evatr.LanguageEnglish)
+	assert.Equal(t, "Invalid call.", msg)
+
+	_, ok := tr.Translate("evatr-9999", "fr")
+	assert.False(t, ok)
+
+	// Custom messages apply to wrapped API errors.
+	err := fmt.Errorf("customer 42: %w", evatr.NewForbiddenError(evatr.StatusInvalidCall, "Fehlerhafter Aufruf."))
+	assert.Equal(t, "Invalid request.", tr.Message(err, "en-GB"))
+	assert.Equal(t, "Fehlerhafter Aufruf.", tr.Message(err, "fr"))
+	assert.Equal(t, "API message", tr.Message(&evatr.Error{Status: "evatr-9999", Message: "API message"}, "fr"))
+	assert.Equal(t, evatr.ErrMissingCity.Error(), tr.Message(evatr.ErrMissingCity, "fr"))
+	assert.Empty(t, tr.Message(nil, "fr"))
+}
@@ -1,6 +1,10 @@
 package evatr
 
-import "time"
+import (
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
 
 // ValidationRequest represents a VAT ID validation request.
 type ValidationRequest struct {
@@ -23,22 +27,30 @@ type ValidationRequest struct {
 	City string `json:"ort,omitempty"`
 }
 
+// NewValidationRequest returns a request for the given parsed VAT IDs.
+func NewValidationRequest(requestingVATID, requestedVATID vatid.VATID) *ValidationRequest {
+	return &ValidationRequest{
+		RequestingVATID: requestingVATID.String(),
+		RequestedVATID:  requestedVATID.String(),
+	}
+}
+
 // ValidationResponse represents a VAT ID validation response.
 type ValidationResponse struct {
 	// Technical ID for the validation request
 	ID string `json:"id,omitempty"`
 
 	// Timestamp of the request
-	RequestTimestamp string `json:"anfrageZeitpunkt"`
+	RequestTimestamp Time `json:"anfrageZeitpunkt"`
 
 	// Date from when the VAT ID is/was valid
-	ValidFrom string `json:"gueltigAb,omitempty"`
+	ValidFrom Time `json:"gueltigAb,omitzero"`
 
 	// Date until when the VAT ID was valid
-	ValidUntil string `json:"gueltigBis,omitempty"`
+	ValidUntil Time `json:"gueltigBis,omitzero"`
 
 	// Status code (e.g., "evatr-0000" for valid)
-	Status string `json:"status"`
+	Status StatusCode `json:"status"`
 
 	// Company name verification result (A/B/C/D)
 	CompanyNameResult VerificationResult `json:"ergFirmenname,omitempty"`
@@ -53,30 +65,30 @@ type ValidationResponse struct {
 	CityResult VerificationResult `json:"ergOrt,omitempty"`
 }
 
-// GetRequestTimestamp parses the request timestamp.
+// GetRequestTimestamp returns the request timestamp.
+//
+// Deprecated: The timestamp is decoded with the response, use RequestTimestamp.
 func (v *ValidationResponse) GetRequestTimestamp() (time.Time, error) {
-	return time.Parse(time.RFC3339, v.RequestTimestamp)
+	return v.RequestTimestamp.Time, nil
 }
 
-// GetValidFrom parses the valid-from date if present.
+// GetValidFrom returns the valid-from date if present.
+//
+// Deprecated: The date is decoded with the response, use ValidFrom.
 func (v *ValidationResponse) GetValidFrom() (time.Time, error) {
-	if v.ValidFrom == "" {
-		return time.Time{}, nil
-	}
-	return time.Parse(time.RFC3339, v.ValidFrom)
+	return v.ValidFrom.Time, nil
 }
 
-// GetValidUntil parses the valid-until date if present.
+// GetValidUntil returns the valid-until date if present.
+//
+// Deprecated: The date is decoded with the response, use ValidUntil.
 func (v *ValidationResponse) GetValidUntil() (time.Time, error) {
-	if v.ValidUntil == "" {
-		return time.Time{}, nil
-	}
-	return time.Parse(time.RFC3339, v.ValidUntil)
+	return v.ValidUntil.Time, nil
 }
 
 // IsValid returns whether the VAT ID is currently valid.
 func (v *ValidationResponse) IsValid() bool {
-	return v.Status == "evatr-0000" || v.Status == "evatr-2008"
+	return v.Status.IsValid()
 }
 
 // VerificationResult represents the result of a qualified validation field comparison.
@@ -99,7 +111,7 @@ const (
 // StatusMessage represents a status message for an error code.
 type StatusMessage struct {
 	// Status code (e.g., "evatr-0000")
-	Status string `json:"status"`
+	Status StatusCode `json:"status"`
 
 	// Category of the status
 	Category string `json:"kategorie"`
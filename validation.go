@@ -2,20 +2,27 @@ package evatr
 
 import (
 	"context"
-	"fmt"
 	"strings"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
 )
 
+// validationPath is the API path of validation requests.
+const validationPath = "/v1/abfrage"
+
 // ValidateVAT validates a VAT ID without company data verification.
 func (c *Client) ValidateVAT(ctx context.Context, requestingVATID, requestedVATID string) (*ValidationResponse, error) {
+	requestingVATID = vatid.Normalize(requestingVATID)
+	requestedVATID = vatid.Normalize(requestedVATID)
+
 	if requestingVATID == "" {
-		return nil, fmt.Errorf("requesting VAT ID is required")
+		return nil, ErrMissingRequestingVATID
 	}
 	if !strings.HasPrefix(requestingVATID, "DE") {
-		return nil, fmt.Errorf("requesting VAT ID must be German")
+		return nil, ErrRequestingVATIDNotGerman
 	}
 	if requestedVATID == "" {
-		return nil, fmt.Errorf("requested VAT ID is required")
+		return nil, ErrMissingRequestedVATID
 	}
 
 	req := &ValidationRequest{
@@ -23,28 +30,26 @@ func (c *Client) ValidateVAT(ctx context.Context, requestingVATID, requestedVATI
 		RequestedVATID:  requestedVATID,
 	}
 
-	var resp ValidationResponse
-	if err := c.doRequest(ctx, "POST", "/v1/abfrage", req, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
+	return c.validate(ctx, req)
 }
 
 // ValidateVATQualified validates a VAT ID with company data verification.
 // Compares provided company information with registered data.
 func (c *Client) ValidateVATQualified(ctx context.Context, requestingVATID, requestedVATID, companyName, city, street, postalCode string) (*ValidationResponse, error) {
+	requestingVATID = vatid.Normalize(requestingVATID)
+	requestedVATID = vatid.Normalize(requestedVATID)
+
 	if requestingVATID == "" {
-		return nil, fmt.Errorf("requesting VAT ID is required")
+		return nil, ErrMissingRequestingVATID
 	}
 	if requestedVATID == "" {
-		return nil, fmt.Errorf("requested VAT ID is required")
+		return nil, ErrMissingRequestedVATID
 	}
 	if companyName == "" {
-		return nil, fmt.Errorf("company name is required for qualified validation")
+		return nil, ErrMissingCompanyName
 	}
 	if city == "" {
-		return nil, fmt.Errorf("city is required for qualified validation")
+		return nil, ErrMissingCity
 	}
 
 	req := &ValidationRequest{
@@ -56,30 +61,102 @@ func (c *Client) ValidateVATQualified(ctx context.Context, requestingVATID, requ
 		PostalCode:      postalCode,
 	}
 
-	var resp ValidationResponse
-	if err := c.doRequest(ctx, "POST", "/v1/abfrage", req, &resp); err != nil {
-		return nil, err
-	}
+	return c.validate(ctx, req)
+}
 
-	return &resp, nil
+// ValidateVATID validates a parsed VAT ID without company data verification.
+func (c *Client) ValidateVATID(ctx context.Context, requestingVATID, requestedVATID vatid.VATID) (*ValidationResponse, error) {
+	return c.ValidateVAT(ctx, requestingVATID.String(), requestedVATID.String())
+}
+
+// ValidateVATIDQualified validates a parsed VAT ID with company data verification.
+func (c *Client) ValidateVATIDQualified(ctx context.Context, requestingVATID, requestedVATID vatid.VATID, companyName, city, street, postalCode string) (*ValidationResponse, error) {
+	return c.ValidateVATQualified(ctx, requestingVATID.String(), requestedVATID.String(), companyName, city, street, postalCode)
 }
 
 // ValidateVATWithRequest validates a VAT ID with a custom request.
+// The VAT IDs in req are normalized, req itself is not modified.
 func (c *Client) ValidateVATWithRequest(ctx context.Context, req *ValidationRequest) (*ValidationResponse, error) {
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, ErrMissingRequest
 	}
+
+	normalized := *req
+	normalized.RequestingVATID = vatid.Normalize(req.RequestingVATID)
+	normalized.RequestedVATID = vatid.Normalize(req.RequestedVATID)
+	req = &normalized
+
 	if req.RequestingVATID == "" {
-		return nil, fmt.Errorf("requesting VAT ID is required")
+		return nil, ErrMissingRequestingVATID
 	}
 	if req.RequestedVATID == "" {
-		return nil, fmt.Errorf("requested VAT ID is required")
+		return nil, ErrMissingRequestedVATID
 	}
 
+	return c.validate(ctx, req)
+}
+
+// validate sends a checked request to the API.
+func (c *Client) validate(ctx context.Context, req *ValidationRequest) (*ValidationResponse, error) {
+	ctx, finish := c.startOperation(ctx, Operation{
+		Name:        OperationValidate,
+		Qualified:   req.CompanyName != "" && req.City != "",
+		CountryCode: countryCode(req.RequestedVATID),
+	})
+
+	resp, cacheHit, err := c.doValidate(ctx, req)
+	c.storeResult(ctx, req, resp, cacheHit, err)
+
+	observed := OperationResult{Err: err, CacheHit: cacheHit}
+	if resp != nil {
+		observed.Status = resp.Status
+	}
+	finish(observed)
+
+	return resp, err
+}
+
+func (c *Client) doValidate(ctx context.Context, req *ValidationRequest) (*ValidationResponse, bool, error) {
+	if err := c.preflightCheck(req); err != nil {
+		return nil, false, err
+	}
+
+	if c.cache != nil {
+		return c.cachedValidate(ctx, req, func() (*ValidationResponse, error) {
+			return c.send(ctx, req)
+		})
+	}
+
+	resp, err := c.send(ctx, req)
+	return resp, false, err
+}
+
+// countryCode returns the country code of a normalized VAT ID, if it has one.
+func countryCode(vatID string) string {
+	if len(vatID) < 2 || vatID[0] < 'A' || vatID[0] > 'Z' || vatID[1] < 'A' || vatID[1] > 'Z' {
+		return ""
+	}
+	return vatID[:2]
+}
+
+// send performs the validation request.
+func (c *Client) send(ctx context.Context, req *ValidationRequest) (*ValidationResponse, error) {
 	var resp ValidationResponse
-	if err := c.doRequest(ctx, "POST", "/v1/abfrage", req, &resp); err != nil {
+	if err := c.doRequest(ctx, "POST", validationPath, req, &resp); err != nil {
 		return nil, err
 	}
 
 	return &resp, nil
 }
+
+// preflightCheck validates both VAT IDs offline when the client was created
+// with WithPreflight. The returned error is a *vatid.Error.
+func (c *Client) preflightCheck(req *ValidationRequest) error {
+	if !c.preflight {
+		return nil
+	}
+	if err := vatid.Validate(req.RequestingVATID); err != nil {
+		return err
+	}
+	return vatid.Validate(req.RequestedVATID)
+}
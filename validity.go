@@ -0,0 +1,187 @@
+package evatr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+)
+
+// Validity is the answer to whether a VAT ID was valid on a date.
+type Validity int
+
+const (
+	// The evidence does not cover the date
+	ValidityUnknown Validity = iota
+
+	// The VAT ID was valid on the date
+	ValidityValid
+
+	// The VAT ID was not valid on the date
+	ValidityInvalid
+)
+
+func (v Validity) String() string {
+	switch v {
+	case ValidityValid:
+		return "valid"
+	case ValidityInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidityOn returns whether the response shows the VAT ID valid on the
+// given day, and why. Days are compared in Europe/Berlin.
+//
+// For evatr-2002 and evatr-2006 the answer follows gueltigAb and gueltigBis
+// (both inclusive). A valid status covers the days from gueltigAb to the
+// request, or to gueltigBis if the API sent it, and without gueltigAb only
+// the day of the request.
+func (v *ValidationResponse) ValidityOn(date time.Time) (Validity, string) {
+	day := dayOf(date)
+	checked := dayOf(v.RequestTimestamp.Time)
+	from, until := dayOf(v.ValidFrom.Time), dayOf(v.ValidUntil.Time)
+
+	switch {
+	case !v.ValidFrom.IsZero() && day.Before(from):
+		if v.Status.IsValid() || v.Status.IsNotYetValid() || v.Status.IsNoLongerValid() {
+			return ValidityInvalid, fmt.Sprintf("%s: valid from %s", v.Status, v.ValidFrom)
+		}
+	case !v.ValidUntil.IsZero() && day.After(until):
+		if v.Status.IsValid() || v.Status.IsNoLongerValid() {
+			return ValidityInvalid, fmt.Sprintf("%s: valid until %s", v.Status, v.ValidUntil)
+		}
+	}
+
+	switch {
+	case v.Status.IsNoLongerValid() && !v.ValidUntil.IsZero():
+		return ValidityValid, fmt.Sprintf("%s: valid from %s until %s", v.Status, orDash(v.ValidFrom), v.ValidUntil)
+	case v.Status.IsNotYetValid() && !v.ValidFrom.IsZero():
+		return ValidityValid, fmt.Sprintf("%s: valid from %s", v.Status, v.ValidFrom)
+	case v.Status.IsValid() && !v.ValidFrom.IsZero() && !v.ValidUntil.IsZero():
+		return ValidityValid, fmt.Sprintf("%s: valid from %s until %s", v.Status, v.ValidFrom, v.ValidUntil)
+	case v.Status.IsValid() && !v.ValidFrom.IsZero() && !v.RequestTimestamp.IsZero() && !day.After(checked):
+		return ValidityValid, fmt.Sprintf("%s: valid since %s", v.Status, v.ValidFrom)
+	case v.RequestTimestamp.IsZero() || !day.Equal(checked):
+		return ValidityUnknown, ""
+	case v.Status.IsValid():
+		return ValidityValid, fmt.Sprintf("%s: valid on the day of the request", v.Status)
+	case v.Status.IsNotYetValid(), v.Status.IsNoLongerValid():
+		return ValidityInvalid, fmt.Sprintf("%s: not valid on the day of the request", v.Status)
+	default:
+		return ValidityUnknown, ""
+	}
+}
+
+// ValidityOn returns whether the stored result shows the VAT ID valid on
+// the given day, and why. An evatr-2001 answer shows it invalid on the day
+// of the check.
+func (r ValidationRecord) ValidityOn(date time.Time) (Validity, string) {
+	if r.Response != nil {
+		return r.Response.ValidityOn(date)
+	}
+	if r.Err != nil && r.Err.Status == StatusVATIDNotAssigned && dayOf(date).Equal(dayOf(r.CheckedAt)) {
+		return ValidityInvalid, fmt.Sprintf("%s: not assigned on the day of the request", r.Err.Status)
+	}
+	return ValidityUnknown, ""
+}
+
+// ValidityEvidence is a stored result that covers the date of a
+// ValidityReport.
+type ValidityEvidence struct {
+	Record   ValidationRecord
+	Validity Validity
+	Reason   string
+}
+
+// ValidityReport answers whether a VAT ID was valid on a date.
+type ValidityReport struct {
+	VATID string
+	Date  time.Time
+
+	Validity Validity
+
+	// Evidence the answer is based on, nil if unknown
+	Basis *ValidityEvidence
+
+	// All stored results covering the date, newest first
+	Evidence []ValidityEvidence
+}
+
+// Explain describes the answer and the evidence it is based on.
+func (r ValidityReport) Explain() string {
+	day := dayOf(r.Date).Format(time.DateOnly)
+	if r.Basis == nil {
+		return fmt.Sprintf("%s: no stored result covers %s", r.VATID, day)
+	}
+
+	rec := r.Basis.Record
+	s := fmt.Sprintf("%s was %s on %s according to the check at %s (%s)",
+		r.VATID, r.Validity, day, rec.CheckedAt.In(berlin).Format(time.RFC3339), r.Basis.Reason)
+	if rec.Response != nil && rec.Response.ID != "" {
+		s += ", response " + rec.Response.ID
+	}
+
+	conflicts := 0
+	for _, e := range r.Evidence {
+		if e.Validity != r.Validity {
+			conflicts++
+		}
+	}
+	if conflicts > 0 {
+		s += fmt.Sprintf("; %d older results disagree", conflicts)
+	}
+	return s
+}
+
+// ValidityOn answers whether a VAT ID was valid on a date, e.g. an invoice
+// date, from the results in the store. The newest result that covers the
+// date decides, since later answers of the API may correct earlier ones,
+// e.g. a retroactive deregistration reported with evatr-2006.
+func ValidityOn(ctx context.Context, store Store, vatID string, date time.Time) (ValidityReport, error) {
+	report := ValidityReport{VATID: vatid.Normalize(vatID), Date: date}
+
+	query := RecordQuery{VATID: report.VATID, Descending: true}
+	for {
+		page, err := store.Query(ctx, query)
+		if err != nil {
+			return report, err
+		}
+
+		for _, rec := range page.Records {
+			if validity, reason := rec.ValidityOn(date); validity != ValidityUnknown {
+				report.Evidence = append(report.Evidence, ValidityEvidence{Record: rec, Validity: validity, Reason: reason})
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	if len(report.Evidence) > 0 {
+		report.Basis = &report.Evidence[0]
+		report.Validity = report.Basis.Validity
+	}
+	return report, nil
+}
+
+// dayOf returns midnight of the day of t in Europe/Berlin.
+func dayOf(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	y, m, d := t.In(berlin).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, berlin)
+}
+
+func orDash(t Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.String()
+}
@@ -0,0 +1,121 @@
+package evatr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hostwithquantum/go-evatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseTime(t *testing.T, s string) evatr.Time {
+	t.Helper()
+	parsed, err := evatr.ParseTime(s)
+	require.NoError(t, err)
+	return parsed
+}
+
+// TestResponseValidityOn tests validity dates of a single response
+func TestResponseValidityOn(t *testing.T) {
+	checked := mustParseTime(t, "2025-09-15T10:00:00+02:00")
+	day := func(s string) time.Time {
+		return mustParseTime(t, s).Time
+	}
+
+	tests := []struct {
+		name     string
+		resp     evatr.ValidationResponse
+		date     time.Time
+		validity evatr.Validity
+	}{
+		{"valid on request day", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked}, day("2025-09-15"), evatr.ValidityValid},
+		{"valid, late evening UTC", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked}, time.Date(2025, 9, 14, 22, 30, 0, 0, time.UTC), evatr.ValidityValid},
+		{"valid, other day", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked}, day("2025-09-14"), evatr.ValidityUnknown},
+		{"valid since", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01")}, day("2024-06-30"), evatr.ValidityValid},
+		{"valid since, before", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01")}, day("2019-12-31"), evatr.ValidityInvalid},
+		{"valid since, after request", evatr.ValidationResponse{Status: evatr.StatusValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01")}, day("2025-10-01"), evatr.ValidityUnknown},
+		{"no longer valid, within", evatr.ValidationResponse{Status: evatr.StatusNoLongerValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01"), ValidUntil: mustParseTime(t, "2025-06-30")}, day("2025-06-30"), evatr.ValidityValid},
+		{"no longer valid, after", evatr.ValidationResponse{Status: evatr.StatusNoLongerValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01"), ValidUntil: mustParseTime(t, "2025-06-30")}, day("2025-07-01"), evatr.ValidityInvalid},
+		{"no longer valid, before", evatr.ValidationResponse{Status: evatr.StatusNoLongerValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2020-01-01"), ValidUntil: mustParseTime(t, "2025-06-30")}, day("2019-12-31"), evatr.ValidityInvalid},
+		{"no longer valid without dates", evatr.ValidationResponse{Status: evatr.StatusNoLongerValid, RequestTimestamp: checked}, day("2025-09-15"), evatr.ValidityInvalid},
+		{"no longer valid without dates, other day", evatr.ValidationResponse{Status: evatr.StatusNoLongerValid, RequestTimestamp: checked}, day("2025-01-01"), evatr.ValidityUnknown},
+		{"not yet valid, before", evatr.ValidationResponse{Status: evatr.StatusNotYetValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2025-10-01")}, day("2025-09-30"), evatr.ValidityInvalid},
+		{"not yet valid, from", evatr.ValidationResponse{Status: evatr.StatusNotYetValid, RequestTimestamp: checked, ValidFrom: mustParseTime(t, "2025-10-01")}, day("2025-10-01"), evatr.ValidityValid},
+		{"transient", evatr.ValidationResponse{Status: evatr.StatusServiceUnavailable1, RequestTimestamp: checked}, day("2025-09-15"), evatr.ValidityUnknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validity, reason := tc.resp.ValidityOn(tc.date)
+			assert.Equal(t, tc.validity, validity)
+			if validity == evatr.ValidityUnknown {
+				assert.Empty(t, reason)
+			} else {
+				assert.Contains(t, reason, string(tc.resp.Status))
+			}
+		})
+	}
+}
+
+// TestValidityOn tests answers from stored history
+func TestValidityOn(t *testing.T) {
+	ctx := t.Context()
+	store := evatr.NewMemoryStore()
+	insert := func(rec evatr.ValidationRecord) {
+		rec.Request = evatr.ValidationRequest{RequestingVATID: "DE123456789", RequestedVATID: "ATU12345678"}
+		_, err := store.Insert(ctx, rec)
+		require.NoError(t, err)
+	}
+
+	// Checked valid in January and March, reported no longer valid since
+	// February in September.
+	insert(evatr.ValidationRecord{
+		CheckedAt: mustParseTime(t, "2025-01-10T09:00:00+01:00").Time,
+		Response:  &evatr.ValidationResponse{ID: "jan", Status: evatr.StatusValid, RequestTimestamp: mustParseTime(t, "2025-01-10T09:00:00+01:00")},
+	})
+	insert(evatr.ValidationRecord{
+		CheckedAt: mustParseTime(t, "2025-03-10T09:00:00+01:00").Time,
+		Response:  &evatr.ValidationResponse{ID: "mar", Status: evatr.StatusValid, RequestTimestamp: mustParseTime(t, "2025-03-10T09:00:00+01:00")},
+	})
+	insert(evatr.ValidationRecord{
+		CheckedAt: mustParseTime(t, "2025-09-15T10:00:00+02:00").Time,
+		Response: &evatr.ValidationResponse{
+			ID:               "sep",
+			Status:           evatr.StatusNoLongerValid,
+			RequestTimestamp: mustParseTime(t, "2025-09-15T10:00:00+02:00"),
+			ValidFrom:        mustParseTime(t, "2020-01-01"),
+			ValidUntil:       mustParseTime(t, "2025-02-28"),
+		},
+	})
+	insert(evatr.ValidationRecord{
+		CheckedAt: mustParseTime(t, "2025-09-16T10:00:00+02:00").Time,
+		Err:       &evatr.Error{StatusCode: 404, Status: evatr.StatusVATIDNotAssigned},
+	})
+
+	report, err := evatr.ValidityOn(ctx, store, "atu 12345678", mustParseTime(t, "2025-01-10").Time)
+	require.NoError(t, err)
+	assert.Equal(t, evatr.ValidityValid, report.Validity)
+	assert.Equal(t, "sep", report.Basis.Record.Response.ID)
+	require.Len(t, report.Evidence, 2)
+	assert.Equal(t, "jan", report.Evidence[1].Record.Response.ID)
+	assert.Equal(t, "ATU12345678 was valid on 2025-01-10 according to the check at 2025-09-15T10:00:00+02:00 (evatr-2006: valid from 2020-01-01 until 2025-02-28), response sep", report.Explain())
+
+	// The later answer corrects the check in March.
+	report, err = evatr.ValidityOn(ctx, store, "ATU12345678", mustParseTime(t, "2025-03-10").Time)
+	require.NoError(t, err)
+	assert.Equal(t, evatr.ValidityInvalid, report.Validity)
+	assert.Len(t, report.Evidence, 2)
+	assert.Contains(t, report.Explain(), "; 1 older results disagree")
+
+	report, err = evatr.ValidityOn(ctx, store, "ATU12345678", mustParseTime(t, "2025-09-16").Time)
+	require.NoError(t, err)
+	assert.Equal(t, evatr.ValidityInvalid, report.Validity)
+	assert.Nil(t, report.Basis.Record.Response)
+
+	report, err = evatr.ValidityOn(ctx, store, "FR12345678901", mustParseTime(t, "2025-01-10").Time)
+	require.NoError(t, err)
+	assert.Equal(t, evatr.ValidityUnknown, report.Validity)
+	assert.Nil(t, report.Basis)
+	assert.Equal(t, "FR12345678901: no stored result covers 2025-01-10", report.Explain())
+}
@@ -0,0 +1,684 @@
+package vatid
+
+import (
+	"strconv"
+	"strings"
+)
+
+// isDigits returns whether s is non-empty and consists of ASCII digits only.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func digit(s string, i int) int {
+	return int(s[i] - '0')
+}
+
+// weightedSum multiplies the leading digits of s with the given weights.
+func weightedSum(s string, weights ...int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += w * digit(s, i)
+	}
+	return sum
+}
+
+// mod returns the remainder of the decimal number s divided by m.
+func mod(s string, m int) int {
+	r := 0
+	for i := 0; i < len(s); i++ {
+		r = (r*10 + digit(s, i)) % m
+	}
+	return r
+}
+
+// luhn returns whether the digits in s pass the Luhn check.
+func luhn(s string) bool {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		d := digit(s, len(s)-1-i)
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// mod11_10 returns whether the digits in s pass the ISO 7064 Mod 11,10 check.
+func mod11_10(s string) bool {
+	product := 10
+	for i := 0; i < len(s)-1; i++ {
+		sum := (digit(s, i) + product) % 10
+		if sum == 0 {
+			sum = 10
+		}
+		product = (2 * sum) % 11
+	}
+	return (product+digit(s, len(s)-1))%10 == 1
+}
+
+// digitsOfLength checks the common "n digits" format.
+func digitsOfLength(number string, lengths ...int) *violation {
+	ok := false
+	for _, l := range lengths {
+		if len(number) == l {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return lengthViolation("expected %s digits, got %d characters", joinInts(lengths), len(number))
+	}
+	if !isDigits(number) {
+		return formatViolation("number must consist of digits only")
+	}
+	return nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// Austria: "U" followed by 8 digits.
+func validateAT(number string) *violation {
+	if len(number) != 9 {
+		return lengthViolation("expected U and 8 digits, got %d characters", len(number))
+	}
+	if number[0] != 'U' || !isDigits(number[1:]) {
+		return formatViolation("number must be U followed by 8 digits")
+	}
+	n := number[1:]
+	sum := 0
+	for i := 0; i < 7; i++ {
+		d := digit(n, i)
+		if i%2 == 1 {
+			d *= 2
+			d = d/10 + d%10
+		}
+		sum += d
+	}
+	if (10-(sum+4)%10)%10 != digit(n, 7) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Belgium: 10 digits starting with 0 or 1.
+func validateBE(number string) *violation {
+	if v := digitsOfLength(number, 10); v != nil {
+		return v
+	}
+	if number[0] != '0' && number[0] != '1' {
+		return formatViolation("number must start with 0 or 1")
+	}
+	first, _ := strconv.Atoi(number[:8])
+	check, _ := strconv.Atoi(number[8:])
+	if 97-first%97 != check {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Bulgaria: 9 digits for legal entities, 10 digits for natural persons,
+// foreigners and others.
+func validateBG(number string) *violation {
+	if v := digitsOfLength(number, 9, 10); v != nil {
+		return v
+	}
+	if len(number) == 9 {
+		check := weightedSum(number, 1, 2, 3, 4, 5, 6, 7, 8) % 11
+		if check == 10 {
+			check = weightedSum(number, 3, 4, 5, 6, 7, 8, 9, 10) % 11 % 10
+		}
+		if check != digit(number, 8) {
+			return checksumViolation()
+		}
+		return nil
+	}
+
+	last := digit(number, 9)
+	// Natural person (EGN)
+	if weightedSum(number, 2, 4, 8, 5, 10, 9, 7, 3, 6)%11%10 == last {
+		return nil
+	}
+	// Foreigner (LNCh)
+	if weightedSum(number, 21, 19, 17, 13, 11, 9, 7, 3, 1)%10 == last {
+		return nil
+	}
+	// Others
+	check := 11 - weightedSum(number, 4, 3, 2, 7, 6, 5, 4, 3, 2)%11
+	if check == 11 {
+		check = 0
+	}
+	if check == last {
+		return nil
+	}
+	return checksumViolation()
+}
+
+// Cyprus: 8 digits followed by a check letter.
+func validateCY(number string) *violation {
+	if len(number) != 9 {
+		return lengthViolation("expected 8 digits and a letter, got %d characters", len(number))
+	}
+	if !isDigits(number[:8]) || !isUpper(number[8]) {
+		return formatViolation("number must be 8 digits followed by a letter")
+	}
+	if number[0] == '2' || number[:2] == "12" {
+		return formatViolation("number must not start with 2 or 12")
+	}
+	odd := [10]int{1, 0, 5, 7, 9, 13, 15, 17, 19, 21}
+	sum := 0
+	for i := 0; i < 8; i++ {
+		if i%2 == 0 {
+			sum += odd[digit(number, i)]
+		} else {
+			sum += digit(number, i)
+		}
+	}
+	if byte('A'+sum%26) != number[8] {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Czech Republic: 8 digits for legal entities, 9 or 10 digits for
+// individuals.
+func validateCZ(number string) *violation {
+	if v := digitsOfLength(number, 8, 9, 10); v != nil {
+		return v
+	}
+	switch {
+	case len(number) == 8:
+		if number[0] == '9' {
+			return formatViolation("legal entity number must not start with 9")
+		}
+		check := (11 - weightedSum(number, 8, 7, 6, 5, 4, 3, 2)%11) % 11
+		if check == 0 {
+			check = 1
+		}
+		if check%10 != digit(number, 7) {
+			return checksumViolation()
+		}
+	case len(number) == 9 && number[0] == '6':
+		// Special individual number
+		check := (8 - (10 - weightedSum(number[1:], 8, 7, 6, 5, 4, 3, 2)%11) + 10) % 10
+		if check != digit(number, 8) {
+			return checksumViolation()
+		}
+	case len(number) == 10:
+		// Birth number
+		if mod(number, 11) != 0 && (mod(number[:9], 11) != 10 || number[9] != '0') {
+			return checksumViolation()
+		}
+	}
+	return nil
+}
+
+// Germany: 9 digits, not starting with 0.
+func validateDE(number string) *violation {
+	if v := digitsOfLength(number, 9); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	if !mod11_10(number) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Denmark: 8 digits, not starting with 0.
+func validateDK(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	if weightedSum(number, 2, 7, 6, 5, 4, 3, 2, 1)%11 != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Estonia: 9 digits starting with 10.
+func validateEE(number string) *violation {
+	if v := digitsOfLength(number, 9); v != nil {
+		return v
+	}
+	if !strings.HasPrefix(number, "10") {
+		return formatViolation("number must start with 10")
+	}
+	if weightedSum(number, 3, 7, 1, 3, 7, 1, 3, 7, 1)%10 != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Greece: 9 digits.
+func validateEL(number string) *violation {
+	if v := digitsOfLength(number, 9); v != nil {
+		return v
+	}
+	if weightedSum(number, 256, 128, 64, 32, 16, 8, 4, 2)%11%10 != digit(number, 8) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Spain: 9 characters, a letter or digit, 7 digits and a letter or digit.
+func validateES(number string) *violation {
+	if len(number) != 9 {
+		return lengthViolation("expected 9 characters, got %d", len(number))
+	}
+	if !isDigits(number[1:8]) {
+		return formatViolation("characters 2 to 8 must be digits")
+	}
+
+	const dniLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+	first, last := number[0], number[8]
+	switch {
+	case first >= '0' && first <= '9':
+		// Spanish national (DNI)
+		if !isUpper(last) {
+			return formatViolation("number must end with a letter")
+		}
+		if dniLetters[mod(number[:8], 23)] != last {
+			return checksumViolation()
+		}
+	case first == 'X' || first == 'Y' || first == 'Z':
+		// Foreigner (NIE)
+		if !isUpper(last) {
+			return formatViolation("number must end with a letter")
+		}
+		prefix := string(rune('0' + first - 'X'))
+		if dniLetters[mod(prefix+number[1:8], 23)] != last {
+			return checksumViolation()
+		}
+	case first == 'K' || first == 'L' || first == 'M':
+		// Spanish nationals without DNI
+		if !isUpper(last) {
+			return formatViolation("number must end with a letter")
+		}
+		if dniLetters[mod(number[1:8], 23)] != last {
+			return checksumViolation()
+		}
+	case strings.IndexByte("ABCDEFGHJNPQRSUVW", first) >= 0:
+		// Legal entity (CIF)
+		sum := 0
+		for i := 1; i < 8; i++ {
+			d := digit(number, i)
+			if i%2 == 1 {
+				d *= 2
+				d = d/10 + d%10
+			}
+			sum += d
+		}
+		check := (10 - sum%10) % 10
+		if last != byte('0'+check) && last != "JABCDEFGHI"[check] {
+			return checksumViolation()
+		}
+	default:
+		return formatViolation("invalid first character %q", first)
+	}
+	return nil
+}
+
+// Finland: 8 digits.
+func validateFI(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	check := 11 - weightedSum(number, 7, 9, 10, 5, 8, 4, 2)%11
+	if check == 11 {
+		check = 0
+	}
+	if check != digit(number, 7) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// France: 2 character key followed by the 9 digit SIREN.
+func validateFR(number string) *violation {
+	if len(number) != 11 {
+		return lengthViolation("expected 11 characters, got %d", len(number))
+	}
+	key, siren := number[:2], number[2:]
+	if !isDigits(siren) {
+		return formatViolation("characters 3 to 11 must be digits")
+	}
+	for i := 0; i < 2; i++ {
+		c := key[i]
+		if !(c >= '0' && c <= '9') && !(isUpper(c) && c != 'I' && c != 'O') {
+			return formatViolation("invalid key character %q", c)
+		}
+	}
+	if isDigits(key) {
+		k, _ := strconv.Atoi(key)
+		if k != (12+3*mod(siren, 97))%97 {
+			return checksumViolation()
+		}
+	}
+	return nil
+}
+
+// Croatia: 11 digits (OIB).
+func validateHR(number string) *violation {
+	if v := digitsOfLength(number, 11); v != nil {
+		return v
+	}
+	if !mod11_10(number) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Hungary: 8 digits.
+func validateHU(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	if weightedSum(number, 9, 7, 3, 1, 9, 7, 3, 1)%10 != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Ireland: 7 digits and one or two letters, or the old format of a digit,
+// a letter or "+" or "*", 5 digits and a letter.
+func validateIE(number string) *violation {
+	if len(number) != 8 && len(number) != 9 {
+		return lengthViolation("expected 8 or 9 characters, got %d", len(number))
+	}
+
+	if len(number) == 8 && isDigits(number[:1]) && !isDigits(number[1:2]) {
+		// Old format, e.g. 8Z49289F
+		if !(isUpper(number[1]) || number[1] == '+' || number[1] == '*') || !isDigits(number[2:7]) || !isUpper(number[7]) {
+			return formatViolation("number must be a digit, a letter, 5 digits and a letter")
+		}
+		number = "0" + number[2:7] + number[:1] + number[7:]
+	}
+
+	if !isDigits(number[:7]) || !isUpper(number[7]) {
+		return formatViolation("number must be 7 digits followed by a letter")
+	}
+	sum := weightedSum(number, 8, 7, 6, 5, 4, 3, 2)
+	if len(number) == 9 {
+		c := number[8]
+		switch {
+		case c == 'W':
+		case c >= 'A' && c <= 'I':
+			sum += 9 * int(c-'A'+1)
+		default:
+			return formatViolation("invalid second letter %q", c)
+		}
+	}
+	if "WABCDEFGHIJKLMNOPQRSTUV"[sum%23] != number[7] {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Italy: 11 digits.
+func validateIT(number string) *violation {
+	if v := digitsOfLength(number, 11); v != nil {
+		return v
+	}
+	if strings.HasPrefix(number, "0000000") {
+		return formatViolation("company number must not be zero")
+	}
+	office, _ := strconv.Atoi(number[7:10])
+	if (office < 1 || office > 100) && office != 120 && office != 121 && office != 888 && office != 999 {
+		return formatViolation("invalid tax office %03d", office)
+	}
+	if !luhn(number) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Lithuania: 9 digits for legal entities, 12 digits for temporary taxpayers.
+func validateLT(number string) *violation {
+	if v := digitsOfLength(number, 9, 12); v != nil {
+		return v
+	}
+	if number[len(number)-2] != '1' {
+		return formatViolation("second to last digit must be 1")
+	}
+	sum := 0
+	for i := 0; i < len(number)-1; i++ {
+		sum += (1 + i%9) * digit(number, i)
+	}
+	check := sum % 11
+	if check == 10 {
+		sum = 0
+		for i := 0; i < len(number)-1; i++ {
+			sum += (1 + (i+2)%9) * digit(number, i)
+		}
+		check = sum % 11 % 10
+	}
+	if check != digit(number, len(number)-1) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Luxembourg: 8 digits.
+func validateLU(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	check, _ := strconv.Atoi(number[6:])
+	if mod(number[:6], 89) != check {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Latvia: 11 digits, legal entities start with a digit greater than 3.
+func validateLV(number string) *violation {
+	if v := digitsOfLength(number, 11); v != nil {
+		return v
+	}
+	switch {
+	case number[0] > '3':
+		if weightedSum(number, 9, 1, 4, 8, 3, 10, 2, 5, 7, 6, 1)%11 != 3 {
+			return checksumViolation()
+		}
+	case strings.HasPrefix(number, "32"):
+		// Personal codes issued since 2017 carry no check digit
+	default:
+		check := (1 - weightedSum(number, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9)) % 11
+		if check < 0 {
+			check += 11
+		}
+		if check != digit(number, 10) {
+			return checksumViolation()
+		}
+	}
+	return nil
+}
+
+// Malta: 8 digits, not starting with 0.
+func validateMT(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	if weightedSum(number, 3, 4, 6, 7, 8, 9, 10, 1)%37 != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Netherlands: 9 digits, "B" and 2 digits.
+func validateNL(number string) *violation {
+	if len(number) != 12 {
+		return lengthViolation("expected 12 characters, got %d", len(number))
+	}
+	if !isDigits(number[:9]) || number[9] != 'B' || !isDigits(number[10:]) {
+		return formatViolation("number must be 9 digits, B and 2 digits")
+	}
+
+	// Numbers derived from the BSN use the eleven test, numbers issued to
+	// sole proprietors since 2020 use ISO 7064 Mod 97,10.
+	if (weightedSum(number, 9, 8, 7, 6, 5, 4, 3, 2)-digit(number, 8))%11 == 0 {
+		return nil
+	}
+	// "NL" becomes "2321", "B" becomes "11"
+	if mod("2321"+number[:9]+"11"+number[10:], 97) == 1 {
+		return nil
+	}
+	return checksumViolation()
+}
+
+// Poland: 10 digits.
+func validatePL(number string) *violation {
+	if v := digitsOfLength(number, 10); v != nil {
+		return v
+	}
+	if weightedSum(number, 6, 5, 7, 2, 3, 4, 5, 6, 7)%11 != digit(number, 9) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Portugal: 9 digits, not starting with 0.
+func validatePT(number string) *violation {
+	if v := digitsOfLength(number, 9); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	check := 11 - weightedSum(number, 9, 8, 7, 6, 5, 4, 3, 2)%11
+	if check >= 10 {
+		check = 0
+	}
+	if check != digit(number, 8) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Romania: 2 to 10 digits, not starting with 0.
+func validateRO(number string) *violation {
+	if len(number) < 2 || len(number) > 10 {
+		return lengthViolation("expected 2 to 10 digits, got %d characters", len(number))
+	}
+	if !isDigits(number) {
+		return formatViolation("number must consist of digits only")
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	padded := strings.Repeat("0", 10-len(number)) + number
+	check := weightedSum(padded, 7, 5, 3, 2, 1, 7, 5, 3, 2) * 10 % 11 % 10
+	if check != digit(padded, 9) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Sweden: 12 digits, the organisation number followed by "01".
+func validateSE(number string) *violation {
+	if v := digitsOfLength(number, 12); v != nil {
+		return v
+	}
+	if !strings.HasSuffix(number, "01") {
+		return formatViolation("number must end with 01")
+	}
+	if !luhn(number[:10]) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Slovenia: 8 digits, not starting with 0.
+func validateSI(number string) *violation {
+	if v := digitsOfLength(number, 8); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	check := 11 - weightedSum(number, 8, 7, 6, 5, 4, 3, 2)%11
+	if check == 10 {
+		check = 0
+	}
+	if check != digit(number, 7) {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Slovakia: 10 digits.
+func validateSK(number string) *violation {
+	if v := digitsOfLength(number, 10); v != nil {
+		return v
+	}
+	if number[0] == '0' {
+		return formatViolation("number must not start with 0")
+	}
+	if strings.IndexByte("234789", number[2]) < 0 {
+		return formatViolation("third digit must be one of 2, 3, 4, 7, 8 or 9")
+	}
+	if mod(number, 11) != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
+
+// Northern Ireland: 9 digits, 12 digits for branches, or GD/HA followed by
+// 3 digits for government departments and health authorities.
+func validateXI(number string) *violation {
+	if len(number) == 5 {
+		prefix := number[:2]
+		if (prefix != "GD" && prefix != "HA") || !isDigits(number[2:]) {
+			return formatViolation("number must be GD or HA followed by 3 digits")
+		}
+		n, _ := strconv.Atoi(number[2:])
+		if prefix == "GD" && n >= 500 {
+			return formatViolation("government department number must be below 500")
+		}
+		if prefix == "HA" && n < 500 {
+			return formatViolation("health authority number must be 500 or above")
+		}
+		return nil
+	}
+
+	if v := digitsOfLength(number, 9, 12); v != nil {
+		return v
+	}
+	sum := weightedSum(number, 8, 7, 6, 5, 4, 3, 2)
+	check, _ := strconv.Atoi(number[7:9])
+	if (sum+check)%97 != 0 && (sum+check+55)%97 != 0 {
+		return checksumViolation()
+	}
+	return nil
+}
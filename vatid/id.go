@@ -0,0 +1,146 @@
+package vatid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// VATID is a normalized VAT ID. The zero value represents "no VAT ID".
+type VATID struct {
+	country string
+	number  string
+}
+
+// Normalize returns the canonical form of a VAT ID as typed by a user:
+// upper case, without whitespace, dots, dashes, slashes and commas. The
+// Greek ISO code "GR" is replaced by "EL" as used by VIES.
+func Normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		switch r {
+		case '.', '-', '/', ',':
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+
+	if strings.HasPrefix(s, "GR") {
+		s = "EL" + s[2:]
+	}
+	return s
+}
+
+// Parse normalizes s and splits it into country code and number. It fails if
+// the country code is not supported or the number is missing. Parse does not
+// verify check digits, use Validate for that.
+func Parse(s string) (VATID, error) {
+	n := Normalize(s)
+	if n == "" {
+		return VATID{}, &Error{VATID: s, Rule: RuleEmpty, Reason: "VAT ID is empty"}
+	}
+	if len(n) < 3 {
+		return VATID{}, &Error{VATID: s, Rule: RuleLength, Reason: "VAT ID is too short"}
+	}
+	if !IsSupported(n[:2]) {
+		return VATID{}, &Error{VATID: s, Rule: RuleCountryCode, Reason: fmt.Sprintf("unknown country code %q", n[:2])}
+	}
+	return VATID{country: n[:2], number: n[2:]}, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for tests and
+// package level variables.
+func MustParse(s string) VATID {
+	id, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// CountryCode returns the two-letter country code (e.g. "DE" or "EL").
+func (id VATID) CountryCode() string {
+	return id.country
+}
+
+// Number returns the national part of the VAT ID without the country code.
+func (id VATID) Number() string {
+	return id.number
+}
+
+// String returns the canonical VAT ID, e.g. "DE136695976".
+func (id VATID) String() string {
+	return id.country + id.number
+}
+
+// IsZero returns whether id is the zero value.
+func (id VATID) IsZero() bool {
+	return id.country == "" && id.number == ""
+}
+
+// Validate checks syntax and check digits of the VAT ID.
+func (id VATID) Validate() error {
+	return Validate(id.String())
+}
+
+// MarshalJSON encodes the VAT ID as a JSON string, the zero value as "".
+func (id VATID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.String())
+}
+
+// UnmarshalJSON decodes and parses a JSON string. An empty string or null
+// results in the zero value.
+func (id *VATID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = VATID{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("vatid: failed to decode VAT ID: %w", err)
+	}
+	return id.set(s)
+}
+
+// Scan implements sql.Scanner for string and []byte columns. NULL results in
+// the zero value.
+func (id *VATID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = VATID{}
+		return nil
+	case string:
+		return id.set(v)
+	case []byte:
+		return id.set(string(v))
+	default:
+		return fmt.Errorf("vatid: cannot scan %T into VATID", src)
+	}
+}
+
+// Value implements driver.Valuer. The zero value is stored as NULL.
+func (id VATID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return id.String(), nil
+}
+
+func (id *VATID) set(s string) error {
+	if s == "" {
+		*id = VATID{}
+		return nil
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
@@ -0,0 +1,84 @@
+package vatid_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests normalization of user input
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input   string
+		country string
+		number  string
+	}{
+		{"DE136695976", "DE", "136695976"},
+		{" de 136.695.976 ", "DE", "136695976"},
+		{"atu-1358-5627", "AT", "U13585627"},
+		{"GR 094259216", "EL", "094259216"},
+		{"NL004495445b01", "NL", "004495445B01"},
+		{"FR 40/303 265 045", "FR", "40303265045"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			id, err := vatid.Parse(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.country, id.CountryCode())
+			assert.Equal(t, tc.number, id.Number())
+			assert.Equal(t, tc.country+tc.number, id.String())
+			assert.NoError(t, id.Validate())
+		})
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, input := range []string{"", " - ", "DE", "US123456789"} {
+			_, err := vatid.Parse(input)
+			assert.Error(t, err, input)
+		}
+	})
+}
+
+// TestVATIDJSON tests JSON round trips
+func TestVATIDJSON(t *testing.T) {
+	type customer struct {
+		VATID vatid.VATID `json:"vatId"`
+	}
+
+	data, err := json.Marshal(customer{VATID: vatid.MustParse("de 136 695 976")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vatId":"DE136695976"}`, string(data))
+
+	var c customer
+	require.NoError(t, json.Unmarshal([]byte(`{"vatId":"atu 13585627"}`), &c))
+	assert.Equal(t, "ATU13585627", c.VATID.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"vatId":null}`), &c))
+	assert.True(t, c.VATID.IsZero())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"vatId":"XX123"}`), &c))
+}
+
+// TestVATIDSQL tests the sql.Scanner and driver.Valuer implementations
+func TestVATIDSQL(t *testing.T) {
+	var id vatid.VATID
+	require.NoError(t, id.Scan([]byte("DE136695976")))
+	assert.Equal(t, "DE136695976", id.String())
+
+	v, err := id.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "DE136695976", v)
+
+	require.NoError(t, id.Scan(nil))
+	assert.True(t, id.IsZero())
+
+	v, err = id.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	assert.Error(t, id.Scan(42))
+}
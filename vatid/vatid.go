@@ -0,0 +1,142 @@
+// Package vatid validates the syntax and check digits of EU VAT IDs offline.
+//
+// The rules follow the formats published by the European Commission for
+// VIES. They catch typos before a request is sent to the BZSt, they do not
+// tell whether a VAT ID is actually assigned.
+package vatid
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Rule identifies the rule a VAT ID violated.
+type Rule string
+
+const (
+	// The VAT ID is empty
+	RuleEmpty Rule = "empty"
+
+	// The country code is not an EU member state (or XI)
+	RuleCountryCode Rule = "country-code"
+
+	// The number has the wrong length for the country
+	RuleLength Rule = "length"
+
+	// The number contains characters not allowed at their position
+	RuleFormat Rule = "format"
+
+	// The check digit(s) do not match
+	RuleChecksum Rule = "checksum"
+)
+
+// Error is returned when a VAT ID fails validation.
+type Error struct {
+	// VAT ID as passed to Validate
+	VATID string
+
+	// Two-letter country code (if it could be determined)
+	CountryCode string
+
+	// Rule that failed
+	Rule Rule
+
+	// Human-readable reason
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.CountryCode != "" {
+		return fmt.Sprintf("vatid: %s: %s rule failed for %s: %s", e.VATID, e.Rule, e.CountryCode, e.Reason)
+	}
+	return fmt.Sprintf("vatid: %s: %s rule failed: %s", e.VATID, e.Rule, e.Reason)
+}
+
+// violation is returned by the per-country checks and turned into an *Error by Validate.
+type violation struct {
+	rule   Rule
+	reason string
+}
+
+func lengthViolation(reason string, args ...any) *violation {
+	return &violation{rule: RuleLength, reason: fmt.Sprintf(reason, args...)}
+}
+
+func formatViolation(reason string, args ...any) *violation {
+	return &violation{rule: RuleFormat, reason: fmt.Sprintf(reason, args...)}
+}
+
+func checksumViolation() *violation {
+	return &violation{rule: RuleChecksum, reason: "check digit does not match"}
+}
+
+// countries maps each supported country code to its validation function.
+var countries = map[string]func(number string) *violation{
+	"AT": validateAT,
+	"BE": validateBE,
+	"BG": validateBG,
+	"CY": validateCY,
+	"CZ": validateCZ,
+	"DE": validateDE,
+	"DK": validateDK,
+	"EE": validateEE,
+	"EL": validateEL,
+	"ES": validateES,
+	"FI": validateFI,
+	"FR": validateFR,
+	"HR": validateHR,
+	"HU": validateHU,
+	"IE": validateIE,
+	"IT": validateIT,
+	"LT": validateLT,
+	"LU": validateLU,
+	"LV": validateLV,
+	"MT": validateMT,
+	"NL": validateNL,
+	"PL": validatePL,
+	"PT": validatePT,
+	"RO": validateRO,
+	"SE": validateSE,
+	"SI": validateSI,
+	"SK": validateSK,
+	"XI": validateXI,
+}
+
+// CountryCodes returns the supported country codes in alphabetical order.
+func CountryCodes() []string {
+	codes := make([]string, 0, len(countries))
+	for code := range countries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// IsSupported returns whether the country code is known to the package.
+func IsSupported(countryCode string) bool {
+	_, ok := countries[countryCode]
+	return ok
+}
+
+// Validate checks a VAT ID consisting of the two-letter country code followed
+// by the national number (e.g. "DE136695976"). The ID must already be in
+// canonical form: upper case and without separators.
+func Validate(id string) error {
+	if id == "" {
+		return &Error{VATID: id, Rule: RuleEmpty, Reason: "VAT ID is empty"}
+	}
+	if len(id) < 3 {
+		return &Error{VATID: id, Rule: RuleLength, Reason: "VAT ID is too short"}
+	}
+
+	country := id[:2]
+	check, ok := countries[country]
+	if !ok {
+		return &Error{VATID: id, Rule: RuleCountryCode, Reason: fmt.Sprintf("unknown country code %q", country)}
+	}
+
+	if v := check(id[2:]); v != nil {
+		return &Error{VATID: id, CountryCode: country, Rule: v.rule, Reason: v.reason}
+	}
+	return nil
+}
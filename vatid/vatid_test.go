@@ -0,0 +1,85 @@
+package vatid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hostwithquantum/go-evatr/vatid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidate checks known valid VAT IDs for every supported country
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"ATU13585627",
+		"BE0403019261",
+		"BG175074752",
+		"CY10259033P",
+		"CZ25123891",
+		"DE136695976",
+		"DK13585628",
+		"EE100931558",
+		"EL094259216",
+		"ESA13585625",
+		"ES54362315K",
+		"ESX5253868R",
+		"FI20774740",
+		"FR40303265045",
+		"HR33392005961",
+		"HU12892312",
+		"IE6433435F",
+		"IE8Z49289F",
+		"IE6433435OA",
+		"IT00743110157",
+		"LT119511515",
+		"LU15027442",
+		"LV40003521600",
+		"MT11679112",
+		"NL004495445B01",
+		"PL8567346215",
+		"PT501964843",
+		"RO18547290",
+		"SE123456789701",
+		"SI50223054",
+		"SK2022749619",
+		"XI980780684",
+		"XIGD001",
+	}
+
+	for _, id := range valid {
+		assert.NoError(t, vatid.Validate(id), id)
+	}
+
+	assert.Len(t, vatid.CountryCodes(), 28)
+}
+
+// TestValidateErrors checks that the failed rule is reported
+func TestValidateErrors(t *testing.T) {
+	testCases := []struct {
+		id   string
+		rule vatid.Rule
+	}{
+		{"", vatid.RuleEmpty},
+		{"GR094259216", vatid.RuleCountryCode},
+		{"US123456789", vatid.RuleCountryCode},
+		{"DE12345678", vatid.RuleLength},
+		{"DE12345678A", vatid.RuleFormat},
+		{"DE136695977", vatid.RuleChecksum},
+		{"ATU13585626", vatid.RuleChecksum},
+		{"ESA13585620", vatid.RuleChecksum},
+		{"NL004495445A01", vatid.RuleFormat},
+		{"XIGD500", vatid.RuleFormat},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			err := vatid.Validate(tc.id)
+			require.Error(t, err)
+
+			var vatErr *vatid.Error
+			require.True(t, errors.As(err, &vatErr))
+			assert.Equal(t, tc.rule, vatErr.Rule)
+		})
+	}
+}